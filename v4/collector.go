@@ -6,6 +6,29 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 )
 
+var (
+	// sizeBuckets are default buckets of histograms that observe an amount of data, from 64B up to 16MB.
+	sizeBuckets = prometheus.ExponentialBuckets(64, 4, 10)
+	// compressionRatioBuckets are default buckets of histograms that observe a ratio between uncompressed and compressed data.
+	compressionRatioBuckets = []float64{0.5, 0.75, 1, 1.25, 1.5, 2, 3, 5, 10, 20}
+	// metadataSizeBuckets are default buckets of histograms that observe the size of headers or trailers, from 32B up to 64KB.
+	metadataSizeBuckets = prometheus.ExponentialBuckets(32, 2, 12)
+	// metadataKeysBuckets are default buckets of histograms that observe the number of headers or trailers keys.
+	metadataKeysBuckets = prometheus.ExponentialBuckets(1, 2, 8)
+	// deadlineBuckets are default buckets of histograms that observe time left until a deadline, from 5ms up to 5m.
+	deadlineBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 300}
+	// connectionDurationBuckets are default buckets of histograms that observe connection lifetime, from 100ms up to 1 day.
+	connectionDurationBuckets = []float64{0.1, 1, 10, 30, 60, 300, 600, 1800, 3600, 7200, 21600, 86400}
+	// requestsPerConnectionBuckets are default buckets of histograms that observe the number of RPCs carried by a connection, from 1 up to ~250k.
+	requestsPerConnectionBuckets = prometheus.ExponentialBuckets(1, 4, 10)
+	// attemptsBuckets are default buckets of histograms that observe the number of attempts made within a call.
+	attemptsBuckets = []float64{1, 2, 3, 4, 5, 10}
+	// a66LatencyBuckets are buckets of latency histograms recommended by gRPC proposal A66.
+	a66LatencyBuckets = []float64{0, 0.00001, 0.00005, 0.0001, 0.0003, 0.0006, 0.0008, 0.001, 0.002, 0.003, 0.004, 0.005, 0.006, 0.008, 0.01, 0.013, 0.016, 0.02, 0.025, 0.03, 0.04, 0.05, 0.065, 0.08, 0.1, 0.13, 0.16, 0.2, 0.25, 0.3, 0.4, 0.5, 0.65, 0.8, 1, 2, 5, 10, 20, 50, 100}
+	// a66SizeBuckets are buckets of size histograms recommended by gRPC proposal A66.
+	a66SizeBuckets = []float64{0, 1024, 2048, 4096, 16384, 65536, 262144, 1048576, 4194304, 16777216, 67108864, 268435456, 1073741824, 4294967296}
+)
+
 func newConnectionsGaugeVec(sub string, labels []string, opts ...CollectorOption) *prometheus.GaugeVec {
 	prototype := prometheus.Opts{
 		Namespace: namespace,
@@ -27,7 +50,8 @@ func newMessageReceivedSizeHistogramVec(sub string, labels []string, opts ...Col
 		Help:      "TODO",
 	}
 	return prometheus.NewHistogramVec(
-		applyHistogramOptions(prototype, opts...), labels,
+		applyHistogramOptions(prototype, opts...),
+		applyLabelOptions(labels, optionalLabelCompression|optionalLabelMessageType, opts...),
 	)
 }
 
@@ -39,7 +63,8 @@ func newMessageSentSizeHistogramVec(sub string, labels []string, opts ...Collect
 		Help:      "TODO",
 	}
 	return prometheus.NewHistogramVec(
-		applyHistogramOptions(prototype, opts...), labels,
+		applyHistogramOptions(prototype, opts...),
+		applyLabelOptions(labels, optionalLabelCompression|optionalLabelMessageType, opts...),
 	)
 }
 
@@ -50,7 +75,10 @@ func newMessagesReceivedTotalCounterVec(sub string, labels []string, opts ...Col
 		Name:      "messages_received_total",
 		Help:      "TODO",
 	}
-	return prometheus.NewCounterVec(prometheus.CounterOpts(applyCollectorOptions(prototype, opts...)), labels)
+	return prometheus.NewCounterVec(
+		prometheus.CounterOpts(applyCollectorOptions(prototype, opts...)),
+		applyLabelOptions(labels, optionalLabelMessageType, opts...),
+	)
 }
 
 func newMessagesSentTotalCounterVec(sub string, labels []string, opts ...CollectorOption) *prometheus.CounterVec {
@@ -62,7 +90,7 @@ func newMessagesSentTotalCounterVec(sub string, labels []string, opts ...Collect
 	}
 	return prometheus.NewCounterVec(
 		prometheus.CounterOpts(applyCollectorOptions(prototype, opts...)),
-		labels,
+		applyLabelOptions(labels, optionalLabelMessageType, opts...),
 	)
 }
 
@@ -75,7 +103,20 @@ func newRequestDurationHistogramVec(sub string, labels []string, opts ...Collect
 	}
 	return prometheus.NewHistogramVec(
 		applyHistogramOptions(prototype, opts...),
-		labels,
+		applyLabelOptions(labels, optionalLabelType, opts...),
+	)
+}
+
+func newRequestDurationSummaryVec(sub string, labels []string, opts ...CollectorOption) *SketchSummaryVec {
+	prototype := SketchSummaryOpts{
+		Namespace: namespace,
+		Subsystem: strings.ToLower(sub),
+		Name:      "request_duration_summary_seconds",
+		Help:      "Sliding window quantiles of RPC duration, computed with DDSketch.",
+	}
+	return NewSketchSummaryVec(
+		applySketchSummaryOptions(prototype, opts...),
+		applyLabelOptions(labels, optionalLabelType, opts...),
 	)
 }
 
@@ -87,7 +128,8 @@ func newRequestsInFlightGaugeVec(sub string, labels []string, opts ...CollectorO
 		Help:      "TODO",
 	}
 	return prometheus.NewGaugeVec(
-		prometheus.GaugeOpts(applyCollectorOptions(prototype, opts...)), labels,
+		prometheus.GaugeOpts(applyCollectorOptions(prototype, opts...)),
+		applyLabelOptions(labels, 0, opts...),
 	)
 }
 
@@ -99,7 +141,8 @@ func newRequestsTotalCounterVec(sub, name, help string, labels []string, opts ..
 		Help:      help,
 	}
 	return prometheus.NewCounterVec(
-		prometheus.CounterOpts(applyCollectorOptions(prototype, opts...)), labels,
+		prometheus.CounterOpts(applyCollectorOptions(prototype, opts...)),
+		applyLabelOptions(labels, optionalLabelType, opts...),
 	)
 }
 
@@ -110,8 +153,496 @@ func newResponsesTotalCounterVec(sub, name, help string, labels []string, opts .
 		Name:      name,
 		Help:      help,
 	}
+	return prometheus.NewCounterVec(
+		prometheus.CounterOpts(applyCollectorOptions(prototype, opts...)),
+		applyLabelOptions(labels, optionalLabelType, opts...),
+	)
+}
+
+func newRequestPhaseDurationHistogramVec(sub string, labels []string, opts ...CollectorOption) *prometheus.HistogramVec {
+	prototype := prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: strings.ToLower(sub),
+		Name:      "request_phase_duration_histogram_seconds",
+		Help:      "Time elapsed from the beginning of an RPC until it reaches a given phase.",
+	}
+	return prometheus.NewHistogramVec(
+		applyHistogramOptions(prototype, opts...),
+		applyLabelOptions(labels, 0, opts...),
+	)
+}
+
+func newRequestWaitDurationHistogramVec(sub string, labels []string, opts ...CollectorOption) *prometheus.HistogramVec {
+	prototype := prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: strings.ToLower(sub),
+		Name:      "request_wait_duration_histogram_seconds",
+		Help:      "Time an RPC waited for a transport before its headers were sent.",
+	}
+	return prometheus.NewHistogramVec(
+		applyHistogramOptions(prototype, opts...),
+		applyLabelOptions(labels, 0, opts...),
+	)
+}
+
+func newDelayedPicksTotalCounterVec(sub string, labels []string, opts ...CollectorOption) *prometheus.CounterVec {
+	prototype := prometheus.Opts{
+		Namespace: namespace,
+		Subsystem: strings.ToLower(sub),
+		Name:      "delayed_picks_total",
+		Help:      "Number of times an RPC blocked by a load balancer received a new picker.",
+	}
+	return prometheus.NewCounterVec(
+		prometheus.CounterOpts(applyCollectorOptions(prototype, opts...)),
+		applyLabelOptions(labels, 0, opts...),
+	)
+}
+
+func newMessageReceivedIntervalHistogramVec(sub string, labels []string, opts ...CollectorOption) *prometheus.HistogramVec {
+	prototype := prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: strings.ToLower(sub),
+		Name:      "message_received_interval_histogram_seconds",
+		Help:      "Time elapsed between two consecutive messages received within the same RPC.",
+	}
+	return prometheus.NewHistogramVec(
+		applyHistogramOptions(prototype, opts...),
+		applyLabelOptions(labels, 0, opts...),
+	)
+}
+
+func newMessageSentIntervalHistogramVec(sub string, labels []string, opts ...CollectorOption) *prometheus.HistogramVec {
+	prototype := prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: strings.ToLower(sub),
+		Name:      "message_sent_interval_histogram_seconds",
+		Help:      "Time elapsed between two consecutive messages sent within the same RPC.",
+	}
+	return prometheus.NewHistogramVec(
+		applyHistogramOptions(prototype, opts...),
+		applyLabelOptions(labels, 0, opts...),
+	)
+}
+
+func newMessagesReceivedPerRequestHistogramVec(sub string, labels []string, opts ...CollectorOption) *prometheus.HistogramVec {
+	prototype := prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: strings.ToLower(sub),
+		Name:      "messages_received_per_request_histogram",
+		Help:      "Number of messages received within a single RPC.",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 11),
+	}
+	return prometheus.NewHistogramVec(
+		applyHistogramOptions(prototype, opts...),
+		applyLabelOptions(labels, 0, opts...),
+	)
+}
+
+func newMessagesSentPerRequestHistogramVec(sub string, labels []string, opts ...CollectorOption) *prometheus.HistogramVec {
+	prototype := prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: strings.ToLower(sub),
+		Name:      "messages_sent_per_request_histogram",
+		Help:      "Number of messages sent within a single RPC.",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 11),
+	}
+	return prometheus.NewHistogramVec(
+		applyHistogramOptions(prototype, opts...),
+		applyLabelOptions(labels, 0, opts...),
+	)
+}
+
+func newPayloadReceivedPerRequestHistogramVec(sub string, labels []string, opts ...CollectorOption) *prometheus.HistogramVec {
+	prototype := prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: strings.ToLower(sub),
+		Name:      "payload_received_per_request_histogram_bytes",
+		Help:      "Total size of messages received within a single RPC.",
+		Buckets:   sizeBuckets,
+	}
+	return prometheus.NewHistogramVec(
+		applyHistogramOptions(prototype, opts...),
+		applyLabelOptions(labels, 0, opts...),
+	)
+}
+
+func newPayloadSentPerRequestHistogramVec(sub string, labels []string, opts ...CollectorOption) *prometheus.HistogramVec {
+	prototype := prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: strings.ToLower(sub),
+		Name:      "payload_sent_per_request_histogram_bytes",
+		Help:      "Total size of messages sent within a single RPC.",
+		Buckets:   sizeBuckets,
+	}
+	return prometheus.NewHistogramVec(
+		applyHistogramOptions(prototype, opts...),
+		applyLabelOptions(labels, 0, opts...),
+	)
+}
+
+func newMessageReceivedWireSizeHistogramVec(sub string, labels []string, opts ...CollectorOption) *prometheus.HistogramVec {
+	prototype := prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: strings.ToLower(sub),
+		Name:      "message_received_wire_size_histogram_bytes",
+		Help:      "Size of messages received, after compression and including gRPC framing.",
+		Buckets:   sizeBuckets,
+	}
+	return prometheus.NewHistogramVec(
+		applyHistogramOptions(prototype, opts...),
+		applyLabelOptions(labels, optionalLabelCompression|optionalLabelMessageType, opts...),
+	)
+}
+
+func newMessageSentWireSizeHistogramVec(sub string, labels []string, opts ...CollectorOption) *prometheus.HistogramVec {
+	prototype := prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: strings.ToLower(sub),
+		Name:      "message_sent_wire_size_histogram_bytes",
+		Help:      "Size of messages sent, after compression and including gRPC framing.",
+		Buckets:   sizeBuckets,
+	}
+	return prometheus.NewHistogramVec(
+		applyHistogramOptions(prototype, opts...),
+		applyLabelOptions(labels, optionalLabelCompression|optionalLabelMessageType, opts...),
+	)
+}
+
+func newMessageReceivedCompressionRatioHistogramVec(sub string, labels []string, opts ...CollectorOption) *prometheus.HistogramVec {
+	prototype := prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: strings.ToLower(sub),
+		Name:      "message_received_compression_ratio_histogram",
+		Help:      "Ratio between uncompressed and compressed size of messages received.",
+		Buckets:   compressionRatioBuckets,
+	}
+	return prometheus.NewHistogramVec(
+		applyHistogramOptions(prototype, opts...),
+		applyLabelOptions(labels, optionalLabelCompression, opts...),
+	)
+}
+
+func newMessageSentCompressionRatioHistogramVec(sub string, labels []string, opts ...CollectorOption) *prometheus.HistogramVec {
+	prototype := prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: strings.ToLower(sub),
+		Name:      "message_sent_compression_ratio_histogram",
+		Help:      "Ratio between uncompressed and compressed size of messages sent.",
+		Buckets:   compressionRatioBuckets,
+	}
+	return prometheus.NewHistogramVec(
+		applyHistogramOptions(prototype, opts...),
+		applyLabelOptions(labels, optionalLabelCompression, opts...),
+	)
+}
+
+func newMetadataReceivedSizeHistogramVec(sub string, labels []string, opts ...CollectorOption) *prometheus.HistogramVec {
+	prototype := prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: strings.ToLower(sub),
+		Name:      "metadata_received_size_histogram_bytes",
+		Help:      "Size of headers and trailers received, computed as an HTTP/2 header list size.",
+		Buckets:   metadataSizeBuckets,
+	}
+	return prometheus.NewHistogramVec(
+		applyHistogramOptions(prototype, opts...),
+		applyLabelOptions(labels, 0, opts...),
+	)
+}
+
+func newMetadataReceivedKeysHistogramVec(sub string, labels []string, opts ...CollectorOption) *prometheus.HistogramVec {
+	prototype := prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: strings.ToLower(sub),
+		Name:      "metadata_received_keys_histogram",
+		Help:      "Number of metadata keys received within headers and trailers.",
+		Buckets:   metadataKeysBuckets,
+	}
+	return prometheus.NewHistogramVec(
+		applyHistogramOptions(prototype, opts...),
+		applyLabelOptions(labels, 0, opts...),
+	)
+}
+
+func newMetadataSentSizeHistogramVec(sub string, labels []string, opts ...CollectorOption) *prometheus.HistogramVec {
+	prototype := prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: strings.ToLower(sub),
+		Name:      "metadata_sent_size_histogram_bytes",
+		Help:      "Size of headers and trailers sent, computed as an HTTP/2 header list size.",
+		Buckets:   metadataSizeBuckets,
+	}
+	return prometheus.NewHistogramVec(
+		applyHistogramOptions(prototype, opts...),
+		applyLabelOptions(labels, 0, opts...),
+	)
+}
+
+func newMetadataSentKeysHistogramVec(sub string, labels []string, opts ...CollectorOption) *prometheus.HistogramVec {
+	prototype := prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: strings.ToLower(sub),
+		Name:      "metadata_sent_keys_histogram",
+		Help:      "Number of metadata keys sent within headers and trailers.",
+		Buckets:   metadataKeysBuckets,
+	}
+	return prometheus.NewHistogramVec(
+		applyHistogramOptions(prototype, opts...),
+		applyLabelOptions(labels, 0, opts...),
+	)
+}
+
+func newRequestDeadlineBudgetHistogramVec(sub string, labels []string, opts ...CollectorOption) *prometheus.HistogramVec {
+	prototype := prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: strings.ToLower(sub),
+		Name:      "request_deadline_budget_histogram_seconds",
+		Help:      "Time left until the deadline of an RPC when it began.",
+		Buckets:   deadlineBuckets,
+	}
+	return prometheus.NewHistogramVec(
+		applyHistogramOptions(prototype, opts...),
+		applyLabelOptions(labels, 0, opts...),
+	)
+}
+
+func newRequestDeadlineHeadroomHistogramVec(sub string, labels []string, opts ...CollectorOption) *prometheus.HistogramVec {
+	prototype := prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: strings.ToLower(sub),
+		Name:      "request_deadline_headroom_histogram_seconds",
+		Help:      "Time left until the deadline of an RPC when it ended.",
+		Buckets:   deadlineBuckets,
+	}
+	return prometheus.NewHistogramVec(
+		applyHistogramOptions(prototype, opts...),
+		applyLabelOptions(labels, 0, opts...),
+	)
+}
+
+func newRequestsWithoutDeadlineTotalCounterVec(sub, name, help string, labels []string, opts ...CollectorOption) *prometheus.CounterVec {
+	prototype := prometheus.Opts{
+		Namespace: namespace,
+		Subsystem: strings.ToLower(sub),
+		Name:      name,
+		Help:      help,
+	}
+	return prometheus.NewCounterVec(
+		prometheus.CounterOpts(applyCollectorOptions(prototype, opts...)),
+		applyLabelOptions(labels, 0, opts...),
+	)
+}
+
+func newConnectionsOpenedTotalCounterVec(sub string, labels []string, opts ...CollectorOption) *prometheus.CounterVec {
+	prototype := prometheus.Opts{
+		Namespace: namespace,
+		Subsystem: strings.ToLower(sub),
+		Name:      "connections_opened_total",
+		Help:      "Number of connections opened.",
+	}
+	return prometheus.NewCounterVec(
+		prometheus.CounterOpts(applyCollectorOptions(prototype, opts...)),
+		labels,
+	)
+}
+
+func newConnectionsClosedTotalCounterVec(sub string, labels []string, opts ...CollectorOption) *prometheus.CounterVec {
+	prototype := prometheus.Opts{
+		Namespace: namespace,
+		Subsystem: strings.ToLower(sub),
+		Name:      "connections_closed_total",
+		Help:      "Number of connections closed.",
+	}
 	return prometheus.NewCounterVec(
 		prometheus.CounterOpts(applyCollectorOptions(prototype, opts...)),
 		labels,
 	)
 }
+
+func newConnectionDurationHistogramVec(sub string, labels []string, opts ...CollectorOption) *prometheus.HistogramVec {
+	prototype := prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: strings.ToLower(sub),
+		Name:      "connection_duration_histogram_seconds",
+		Help:      "Time a connection stayed open.",
+		Buckets:   connectionDurationBuckets,
+	}
+	return prometheus.NewHistogramVec(
+		applyHistogramOptions(prototype, opts...),
+		labels,
+	)
+}
+
+func newIdleConnectionsGaugeVec(sub string, labels []string, opts ...CollectorOption) *prometheus.GaugeVec {
+	prototype := prometheus.Opts{
+		Namespace: namespace,
+		Subsystem: strings.ToLower(sub),
+		Name:      "idle_connections",
+		Help:      "Number of open connections that carry no RPCs.",
+	}
+	return prometheus.NewGaugeVec(
+		prometheus.GaugeOpts(applyCollectorOptions(prototype, opts...)),
+		labels,
+	)
+}
+
+func newRequestsPerConnectionHistogramVec(sub string, labels []string, opts ...CollectorOption) *prometheus.HistogramVec {
+	prototype := prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: strings.ToLower(sub),
+		Name:      "requests_per_connection_histogram",
+		Help:      "Number of RPCs carried by a single connection during its lifetime.",
+		Buckets:   requestsPerConnectionBuckets,
+	}
+	return prometheus.NewHistogramVec(
+		applyHistogramOptions(prototype, opts...),
+		labels,
+	)
+}
+
+func newMaxConcurrentStreamsGaugeVec(sub string, labels []string, opts ...CollectorOption) *prometheus.GaugeVec {
+	prototype := prometheus.Opts{
+		Namespace: namespace,
+		Subsystem: strings.ToLower(sub),
+		Name:      "max_concurrent_streams",
+		Help:      "Highest number of RPCs concurrently active on a single connection, currently and since the start, next to the configured limit.",
+	}
+	return prometheus.NewGaugeVec(
+		prometheus.GaugeOpts(applyCollectorOptions(prototype, opts...)),
+		labels,
+	)
+}
+
+func newAttemptsPerCallHistogramVec(sub string, labels []string, opts ...CollectorOption) *prometheus.HistogramVec {
+	prototype := prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: strings.ToLower(sub),
+		Name:      "attempts_per_call_histogram",
+		Help:      "Number of attempts made within a single call.",
+		Buckets:   attemptsBuckets,
+	}
+	return prometheus.NewHistogramVec(
+		applyHistogramOptions(prototype, opts...),
+		applyLabelOptions(labels, 0, opts...),
+	)
+}
+
+func newRetriedCallsTotalCounterVec(sub string, labels []string, opts ...CollectorOption) *prometheus.CounterVec {
+	prototype := prometheus.Opts{
+		Namespace: namespace,
+		Subsystem: strings.ToLower(sub),
+		Name:      "retried_calls_total",
+		Help:      "Number of calls that required more than one attempt.",
+	}
+	return prometheus.NewCounterVec(
+		prometheus.CounterOpts(applyCollectorOptions(prototype, opts...)),
+		applyLabelOptions(labels, 0, opts...),
+	)
+}
+
+func newTransparentRetriesTotalCounterVec(sub string, labels []string, opts ...CollectorOption) *prometheus.CounterVec {
+	prototype := prometheus.Opts{
+		Namespace: namespace,
+		Subsystem: strings.ToLower(sub),
+		Name:      "transparent_retries_total",
+		Help:      "Number of attempts transparently retried by gRPC.",
+	}
+	return prometheus.NewCounterVec(
+		prometheus.CounterOpts(applyCollectorOptions(prototype, opts...)),
+		applyLabelOptions(labels, 0, opts...),
+	)
+}
+
+func newStartedTotalCounterVec(sub, name, help string, labels []string, opts ...CollectorOption) *prometheus.CounterVec {
+	prototype := prometheus.Opts{
+		Namespace: namespace,
+		Subsystem: strings.ToLower(sub),
+		Name:      name,
+		Help:      help,
+	}
+	return prometheus.NewCounterVec(
+		prometheus.CounterOpts(applyCollectorOptions(prototype, opts...)),
+		applyLabelOptions(labels, 0, opts...),
+	)
+}
+
+func newLatencyHistogramVec(sub, name, help string, labels []string, opts ...CollectorOption) *prometheus.HistogramVec {
+	prototype := prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: strings.ToLower(sub),
+		Name:      name,
+		Help:      help,
+		Buckets:   a66LatencyBuckets,
+	}
+	return prometheus.NewHistogramVec(
+		applyHistogramOptions(prototype, opts...),
+		applyLabelOptions(labels, 0, opts...),
+	)
+}
+
+func newTotalCompressedMessageSizeHistogramVec(sub, name, help string, labels []string, opts ...CollectorOption) *prometheus.HistogramVec {
+	prototype := prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: strings.ToLower(sub),
+		Name:      name,
+		Help:      help,
+		Buckets:   a66SizeBuckets,
+	}
+	return prometheus.NewHistogramVec(
+		applyHistogramOptions(prototype, opts...),
+		applyLabelOptions(labels, 0, opts...),
+	)
+}
+
+func newHandledTotalCounterVec(sub, help string, labels []string, opts ...CollectorOption) *prometheus.CounterVec {
+	prototype := prometheus.Opts{
+		Namespace: namespace,
+		Subsystem: strings.ToLower(sub),
+		Name:      "handled_total",
+		Help:      help,
+	}
+	return prometheus.NewCounterVec(
+		prometheus.CounterOpts(applyCollectorOptions(prototype, opts...)),
+		applyLabelOptions(labels, 0, opts...),
+	)
+}
+
+func newMsgReceivedTotalCounterVec(sub, help string, labels []string, opts ...CollectorOption) *prometheus.CounterVec {
+	prototype := prometheus.Opts{
+		Namespace: namespace,
+		Subsystem: strings.ToLower(sub),
+		Name:      "msg_received_total",
+		Help:      help,
+	}
+	return prometheus.NewCounterVec(
+		prometheus.CounterOpts(applyCollectorOptions(prototype, opts...)),
+		applyLabelOptions(labels, 0, opts...),
+	)
+}
+
+func newMsgSentTotalCounterVec(sub, help string, labels []string, opts ...CollectorOption) *prometheus.CounterVec {
+	prototype := prometheus.Opts{
+		Namespace: namespace,
+		Subsystem: strings.ToLower(sub),
+		Name:      "msg_sent_total",
+		Help:      help,
+	}
+	return prometheus.NewCounterVec(
+		prometheus.CounterOpts(applyCollectorOptions(prototype, opts...)),
+		applyLabelOptions(labels, 0, opts...),
+	)
+}
+
+func newHandlingSecondsHistogramVec(sub, help string, labels []string, opts ...CollectorOption) *prometheus.HistogramVec {
+	prototype := prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: strings.ToLower(sub),
+		Name:      "handling_seconds",
+		Help:      help,
+		Buckets:   prometheus.DefBuckets,
+	}
+	return prometheus.NewHistogramVec(
+		applyHistogramOptions(prototype, opts...),
+		applyLabelOptions(labels, 0, opts...),
+	)
+}
@@ -54,6 +54,76 @@
 //  grpc_server_requests_received_total
 //  grpc_server_responses_sent_total
 //
+// Additionally, some metrics are not a part of the default coordinators, as they are either costly or useful only in specific cases.
+// They can be enabled by passing their stats handlers to NewStatsHandler:
+//
+//  grpc_client_connection_duration_histogram_seconds
+//  grpc_client_connections_closed_total
+//  grpc_client_connections_opened_total
+//  grpc_client_attempts_per_call_histogram
+//  grpc_client_delayed_picks_total
+//  grpc_client_message_received_compression_ratio_histogram
+//  grpc_client_message_received_interval_histogram_seconds
+//  grpc_client_message_received_wire_size_histogram_bytes
+//  grpc_client_message_sent_compression_ratio_histogram
+//  grpc_client_message_sent_interval_histogram_seconds
+//  grpc_client_message_sent_wire_size_histogram_bytes
+//  grpc_client_messages_received_per_request_histogram
+//  grpc_client_messages_sent_per_request_histogram
+//  grpc_client_metadata_received_keys_histogram
+//  grpc_client_metadata_received_size_histogram_bytes
+//  grpc_client_metadata_sent_keys_histogram
+//  grpc_client_metadata_sent_size_histogram_bytes
+//  grpc_client_payload_received_per_request_histogram_bytes
+//  grpc_client_payload_sent_per_request_histogram_bytes
+//  grpc_client_request_deadline_budget_histogram_seconds
+//  grpc_client_request_deadline_headroom_histogram_seconds
+//  grpc_client_request_phase_duration_histogram_seconds
+//  grpc_client_request_wait_duration_histogram_seconds
+//  grpc_client_requests_sent_without_deadline_total
+//  grpc_client_retried_calls_total
+//  grpc_client_transparent_retries_total
+//  grpc_server_connection_duration_histogram_seconds
+//  grpc_server_connections_closed_total
+//  grpc_server_connections_opened_total
+//  grpc_server_idle_connections
+//  grpc_server_max_concurrent_streams
+//  grpc_server_message_received_compression_ratio_histogram
+//  grpc_server_message_received_interval_histogram_seconds
+//  grpc_server_message_received_wire_size_histogram_bytes
+//  grpc_server_message_sent_compression_ratio_histogram
+//  grpc_server_message_sent_interval_histogram_seconds
+//  grpc_server_message_sent_wire_size_histogram_bytes
+//  grpc_server_messages_received_per_request_histogram
+//  grpc_server_messages_sent_per_request_histogram
+//  grpc_server_metadata_received_keys_histogram
+//  grpc_server_metadata_received_size_histogram_bytes
+//  grpc_server_metadata_sent_keys_histogram
+//  grpc_server_metadata_sent_size_histogram_bytes
+//  grpc_server_payload_received_per_request_histogram_bytes
+//  grpc_server_payload_sent_per_request_histogram_bytes
+//  grpc_server_request_deadline_budget_histogram_seconds
+//  grpc_server_request_phase_duration_histogram_seconds
+//  grpc_server_requests_per_connection_histogram
+//  grpc_server_requests_received_without_deadline_total
+//
+// gRPC reports every attempt of a call (e.g. a retry) as a separate RPC.
+// Metrics that describe calls, like grpc_client_attempts_per_call_histogram, additionally require
+// StatsHandler.UnaryClientInterceptor and StatsHandler.StreamClientInterceptor to be installed.
+//
+// Presets
+//
+// A66ClientStatsHandler and A66ServerStatsHandler are coordinators that expose metrics named and shaped after
+// gRFC A66 (OpenTelemetry metrics), e.g. grpc_client_attempt_duration_seconds or grpc_server_call_duration_seconds.
+// They make it possible to share dashboards with other gRPC implementations.
+// The grpc_target label and grpc_client_call_duration_seconds are only available if the client interceptors are installed.
+// A66ServerStatsHandler reports methods that are not registered as other, once they are known thanks to StatsHandler.RegisterServiceInfo.
+//
+// GoGRPCPrometheusClientStatsHandler and GoGRPCPrometheusServerStatsHandler reproduce series exposed by go-grpc-prometheus,
+// e.g. grpc_server_handled_total or grpc_server_handling_seconds, including the grpc_type label.
+// They are meant to ease migration, existing dashboards and alerts keep working.
+// On the client side, calls rather than attempts are counted, as long as the client interceptors are installed.
+//
 // Configuration
 //
 // The package does not require any configuration whatsoever but makes it possible.
@@ -72,6 +142,30 @@
 // Thanks to that, it is possible to pass options related to stats handlers and collectors to coordinator constructors.
 // Constructors take care of moving options to the correct receivers.
 //
+// Metrics of promgrpc v3 are available through V3ClientStatsHandler and V3ServerStatsHandler.
+// For a transition period, MigrationClientStatsHandler and MigrationServerStatsHandler emit v3 and v4 series side by side.
+//
+// RPCs that are not worth measuring, like health checks, can be skipped altogether by passing StatsHandlerWithMethodFilter
+// to a coordinator constructor, e.g. together with MethodDenylist("grpc.health.v1.Health/*").
+//
+// By default, every method a client calls becomes a distinct grpc_service and grpc_method label value, even if a server does not expose it.
+// StatsHandlerWithRegisteredMethods and StatsHandler.RegisterServiceInfo limit them to known methods, others are reported as unknown.
+//
+// Labels common to all RPC related metrics, e.g. a tenant, can be added with WithCustomLabels.
+// Their values are computed once per RPC, by the coordinator.
+// Incoming metadata, e.g. x-tenant-tier, can be mapped to labels with WithMetadataLabels.
+// Each MetadataLabel comes with an allowlist of values, anything else is reported as other, so the number of series stays bounded.
+//
+// StatsHandler.Preinitialize creates series of every method a server exposes up front, so that rate() and absence alerts work from the start.
+//
+// Histogram buckets can be set per metric using CollectorWithBuckets.
+// If some methods require a different layout, e.g. batch jobs that take minutes next to cache lookups that take microseconds,
+// NewMethodHistogramVecs together with StatsHandlerWithMethodVecs route observations of those methods to dedicated vectors.
+//
+// If accurate high quantiles are more important than aggregation across instances, request duration stats handlers
+// accept NewServerRequestDurationSummaryVec and NewClientRequestDurationSummaryVec instead of histograms.
+// They keep a DDSketch per series and expose p50, p90, p99 and p999 over a sliding time window as a Prometheus summary.
+//
 // Mixing both strategies described above will give even greater freedom.
 // However, if that is even not enough, it is possible to reimplement an entire stack for a given metric or metrics.
 package promgrpc
@@ -0,0 +1,134 @@
+package sketch
+
+import (
+	"math"
+)
+
+// DDSketch is a mergeable quantile sketch with relative-error guarantees.
+// A value returned for any quantile is within the relative accuracy of the exact value.
+// Memory is bounded by the maximum number of bins, once exceeded, the lowest bins are collapsed.
+// Since the sketch is meant for durations, values lower or equal to zero are counted as zero.
+// It is not safe for concurrent use.
+//
+// LINK: https://arxiv.org/abs/1908.10693
+type DDSketch struct {
+	gamma    float64
+	logGamma float64
+	maxBins  int
+	bins     []uint64
+	// offset is the index of the first bin.
+	offset int
+	zero   uint64
+	count  uint64
+}
+
+// New allocates a new DDSketch of given relative accuracy (e.g. 0.01 for 1%) and maximum number of bins.
+func New(relativeAccuracy float64, maxBins int) *DDSketch {
+	gamma := (1 + relativeAccuracy) / (1 - relativeAccuracy)
+	return &DDSketch{
+		gamma:    gamma,
+		logGamma: math.Log(gamma),
+		maxBins:  maxBins,
+	}
+}
+
+// Add records a single value.
+func (s *DDSketch) Add(v float64) {
+	s.count++
+	if v <= 0 || math.IsNaN(v) {
+		s.zero++
+		return
+	}
+	s.addToBin(int(math.Ceil(math.Log(v)/s.logGamma)), 1)
+}
+
+// Merge adds all values recorded by the other sketch.
+// Both sketches have to be of the same relative accuracy.
+func (s *DDSketch) Merge(o *DDSketch) {
+	s.count += o.count
+	s.zero += o.zero
+	for i, n := range o.bins {
+		if n > 0 {
+			s.addToBin(o.offset+i, n)
+		}
+	}
+}
+
+// Quantile returns an approximation of the given quantile.
+// It returns NaN if the sketch is empty.
+func (s *DDSketch) Quantile(q float64) float64 {
+	if s.count == 0 || q < 0 || q > 1 {
+		return math.NaN()
+	}
+
+	rank := q * float64(s.count-1)
+	n := float64(s.zero)
+	if n > rank {
+		return 0
+	}
+	for i, c := range s.bins {
+		n += float64(c)
+		if n > rank {
+			return s.value(s.offset + i)
+		}
+	}
+	return s.value(s.offset + len(s.bins) - 1)
+}
+
+// Count returns the number of recorded values.
+func (s *DDSketch) Count() uint64 {
+	return s.count
+}
+
+// Reset removes all recorded values, allocated memory is kept.
+func (s *DDSketch) Reset() {
+	clear(s.bins)
+	s.bins = s.bins[:0]
+	s.offset = 0
+	s.zero = 0
+	s.count = 0
+}
+
+// value returns a value that represents a bin, it is equally distant, relatively, from both bin boundaries.
+func (s *DDSketch) value(index int) float64 {
+	return 2 * math.Pow(s.gamma, float64(index)) / (1 + s.gamma)
+}
+
+func (s *DDSketch) addToBin(index int, n uint64) {
+	switch {
+	case len(s.bins) == 0:
+		s.bins = append(s.bins, n)
+		s.offset = index
+		return
+	case index < s.offset:
+		if s.offset-index+len(s.bins) > s.maxBins && s.maxBins > 0 {
+			// The bin would be collapsed anyway.
+			s.bins[0] += n
+			return
+		}
+		grown := make([]uint64, s.offset-index+len(s.bins))
+		copy(grown[s.offset-index:], s.bins)
+		s.bins = grown
+		s.offset = index
+	case index >= s.offset+len(s.bins):
+		s.bins = append(s.bins, make([]uint64, index-s.offset-len(s.bins)+1)...)
+	}
+	s.bins[index-s.offset] += n
+
+	if s.maxBins > 0 && len(s.bins) > s.maxBins {
+		s.collapse()
+	}
+}
+
+// collapse merges the lowest bins, so that the highest quantiles keep their accuracy.
+func (s *DDSketch) collapse() {
+	excess := len(s.bins) - s.maxBins
+
+	var n uint64
+	for _, c := range s.bins[:excess+1] {
+		n += c
+	}
+	s.bins[excess] = n
+	s.bins = append(s.bins[:0], s.bins[excess:]...)
+	s.offset += excess
+}
@@ -0,0 +1,94 @@
+package sketch_test
+
+import (
+	"math"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4/internal/sketch"
+)
+
+const relativeAccuracy = 0.01
+
+func TestDDSketch_Quantile(t *testing.T) {
+	var (
+		s      = sketch.New(relativeAccuracy, 2048)
+		values []float64
+	)
+	// From microseconds up to minutes.
+	for v := 0.000001; v < 600; v *= 1.001 {
+		s.Add(v)
+		values = append(values, v)
+	}
+	sort.Float64s(values)
+
+	for _, q := range []float64{0, 0.5, 0.9, 0.99, 0.999, 1} {
+		exact := values[int(q*float64(len(values)-1))]
+		if got := s.Quantile(q); math.Abs(got-exact) > exact*relativeAccuracy {
+			t.Errorf("quantile %g: expected %g ±1%%, but got %g", q, exact, got)
+		}
+	}
+	if s.Count() != uint64(len(values)) {
+		t.Errorf("wrong count: %d", s.Count())
+	}
+}
+
+func TestDDSketch_Merge(t *testing.T) {
+	a := sketch.New(relativeAccuracy, 2048)
+	b := sketch.New(relativeAccuracy, 2048)
+	for i := 1; i <= 100; i++ {
+		a.Add(float64(i))
+		b.Add(float64(i + 100))
+	}
+	a.Merge(b)
+
+	if a.Count() != 200 {
+		t.Fatalf("wrong count: %d", a.Count())
+	}
+	if got := a.Quantile(0.99); math.Abs(got-199) > 199*relativeAccuracy {
+		t.Fatalf("expected 199 ±1%%, but got %g", got)
+	}
+}
+
+func TestDDSketch_maxBins(t *testing.T) {
+	s := sketch.New(relativeAccuracy, 64)
+	for v := 0.000001; v < 600; v *= 1.001 {
+		s.Add(v)
+	}
+	s.Add(0)
+
+	// Lowest bins are collapsed, the highest quantiles keep their accuracy.
+	if got := s.Quantile(1); math.Abs(got-600) > 600*relativeAccuracy {
+		t.Fatalf("expected 600 ±1%%, but got %g", got)
+	}
+	if got := s.Quantile(0); got != 0 {
+		t.Fatalf("expected 0, but got %g", got)
+	}
+}
+
+func TestDDSketch_empty(t *testing.T) {
+	if got := sketch.New(relativeAccuracy, 2048).Quantile(0.5); !math.IsNaN(got) {
+		t.Fatalf("expected NaN, but got %g", got)
+	}
+}
+
+func TestWindow(t *testing.T) {
+	var (
+		w   = sketch.NewWindow(time.Minute, 3, relativeAccuracy, 2048)
+		now = time.Now()
+	)
+
+	w.Add(now, 100)
+	w.Add(now.Add(25*time.Second), 1)
+	if got := w.Quantiles(now.Add(30*time.Second), []float64{1}); math.Abs(got[0]-100) > 100*relativeAccuracy {
+		t.Fatalf("expected 100 ±1%%, but got %g", got[0])
+	}
+	// The first age bucket is gone, the second one is still within the window.
+	if got := w.Quantiles(now.Add(time.Minute), []float64{1}); math.Abs(got[0]-1) > relativeAccuracy {
+		t.Fatalf("expected 1 ±1%%, but got %g", got[0])
+	}
+	if got := w.Quantiles(now.Add(time.Hour), []float64{1}); !math.IsNaN(got[0]) {
+		t.Fatalf("expected NaN, but got %g", got[0])
+	}
+}
@@ -0,0 +1,78 @@
+package sketch
+
+import (
+	"time"
+)
+
+// Window is a sliding time window of sketches.
+// It is a ring of sketches, each covering an equal fraction of the window.
+// Values are recorded by the most recent sketch, the oldest one is reset once its fraction of the window passes.
+// Quantiles are computed from all sketches merged together.
+// It is not safe for concurrent use.
+type Window struct {
+	sketches []*DDSketch
+	merged   *DDSketch
+	head     int
+	// expiry is the moment the head sketch stops recording values.
+	expiry time.Time
+	width  time.Duration
+	maxAge time.Duration
+}
+
+// NewWindow allocates a new Window that spans the max age and is divided into a given number of age buckets.
+// Sketches are of the given relative accuracy and maximum number of bins.
+func NewWindow(maxAge time.Duration, ageBuckets int, relativeAccuracy float64, maxBins int) *Window {
+	w := &Window{
+		sketches: make([]*DDSketch, ageBuckets),
+		merged:   New(relativeAccuracy, maxBins),
+		width:    maxAge / time.Duration(ageBuckets),
+		maxAge:   maxAge,
+	}
+	for i := range w.sketches {
+		w.sketches[i] = New(relativeAccuracy, maxBins)
+	}
+	return w
+}
+
+// Add records a single value at a given time.
+func (w *Window) Add(now time.Time, v float64) {
+	w.rotate(now)
+	w.sketches[w.head].Add(v)
+}
+
+// Quantiles returns approximations of given quantiles of values recorded within the window.
+// Each of them is NaN if no values were recorded.
+func (w *Window) Quantiles(now time.Time, qs []float64) []float64 {
+	w.rotate(now)
+
+	w.merged.Reset()
+	for _, s := range w.sketches {
+		w.merged.Merge(s)
+	}
+
+	res := make([]float64, len(qs))
+	for i, q := range qs {
+		res[i] = w.merged.Quantile(q)
+	}
+	return res
+}
+
+func (w *Window) rotate(now time.Time) {
+	if w.expiry.IsZero() {
+		w.expiry = now.Add(w.width)
+		return
+	}
+	if now.Sub(w.expiry) >= w.maxAge {
+		// Nothing recorded so far is within the window anymore.
+		for _, s := range w.sketches {
+			s.Reset()
+		}
+		w.expiry = now.Add(w.width)
+		return
+	}
+	for !now.Before(w.expiry) {
+		w.head = (w.head + 1) % len(w.sketches)
+		w.sketches[w.head].Reset()
+		w.expiry = w.expiry.Add(w.width)
+	}
+}
@@ -36,6 +36,13 @@ func AssertMetricValue(t *testing.T, g prometheus.Gatherer, n string, exp float6
 					case strings.HasSuffix(n, "_count"):
 						got += float64(metric.GetHistogram().GetSampleCount())
 					}
+				case prommodel.MetricType_SUMMARY:
+					switch {
+					case strings.HasSuffix(n, "_sum"):
+						got += metric.GetSummary().GetSampleSum()
+					case strings.HasSuffix(n, "_count"):
+						got += float64(metric.GetSummary().GetSampleCount())
+					}
 				}
 			}
 			if got != exp {
@@ -2,7 +2,12 @@ package promgrpc
 
 import (
 	"context"
+	"strings"
+	"unicode/utf8"
 
+	"github.com/prometheus/client_golang/prometheus"
+
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/stats"
 )
 
@@ -14,6 +19,43 @@ const (
 	labelRemoteAddr      = "grpc_remote_addr"
 	labelLocalAddr       = "grpc_local_addr"
 	labelClientUserAgent = "grpc_client_user_agent"
+	labelPhase           = "grpc_phase"
+	labelCompression     = "grpc_compression"
+	labelMetadataKind    = "grpc_metadata_kind"
+	labelSource          = "grpc_source"
+	labelStatus          = "grpc_status"
+	labelTarget          = "grpc_target"
+	labelType            = "grpc_type"
+	labelMessageType     = "grpc_message_type"
+)
+
+const (
+	phaseInHeader        = "in_header"
+	phaseFirstInPayload  = "first_in_payload"
+	phaseFirstOutPayload = "first_out_payload"
+	phaseEnd             = "end"
+)
+
+// otherValue is reported instead of a value that is not allowed, e.g. a metadata value (see MetadataLabel)
+// or a method that is not registered (see A66ServerStatsHandler).
+const otherValue = "other"
+
+const (
+	metadataKindHeader  = "header"
+	metadataKindTrailer = "trailer"
+)
+
+const (
+	rpcTypeUnary        = "unary"
+	rpcTypeClientStream = "client_stream"
+	rpcTypeServerStream = "server_stream"
+	rpcTypeBidiStream   = "bidi_stream"
+)
+
+const (
+	sourceObserved   = "observed"
+	sourceConfigured = "configured"
+	sourcePeak       = "peak"
 )
 
 type rpcTagLabels struct {
@@ -21,6 +63,78 @@ type rpcTagLabels struct {
 	service         string
 	method          string
 	clientUserAgent string
+	// fullMethod is a method name including the service name, but without the leading slash, e.g. package.Service/Method.
+	fullMethod string
+	// target is a canonical target of a client connection, known only if the call was intercepted.
+	target string
+	// attempt is a number of the attempt within a call, starting from one, known only if the call was intercepted.
+	attempt int64
+	// registered is false if the method is known not to be exposed by a server, see StatsHandler.RegisterServiceInfo.
+	registered bool
+	// Fields below are not known during TagRPC stage.
+	// They are set by the coordinator once related headers are reported.
+	// Headers are always reported before the payloads, so no synchronization is required.
+	receivedCompression string
+	sentCompression     string
+	// custom are values of custom labels, see WithCustomLabels.
+	custom []string
+	// metadata are values of metadata labels, see WithMetadataLabels.
+	metadata []string
+	// rpcType is known once Begin is reported.
+	// On the server side, it is not available to stats triggered by the incoming headers.
+	rpcType string
+}
+
+// compression returns a compressor name used to encode a given payload.
+func (l *rpcTagLabels) compression(stat stats.RPCStats) string {
+	switch stat.(type) {
+	case *stats.InPayload:
+		return l.receivedCompression
+	case *stats.OutPayload:
+		return l.sentCompression
+	default:
+		return notAvailable
+	}
+}
+
+// intercepted reports whether the RPC is an attempt of a call observed by the coordinator interceptors.
+// If so, stats handlers that operate on calls are able to count the call once, see callStatsHandler.
+func (l *rpcTagLabels) intercepted() bool {
+	return l.attempt > 0
+}
+
+// a66Method returns a method name as defined by gRFC A66, methods known not to be registered are reported as other.
+func (l *rpcTagLabels) a66Method() string {
+	if l.registered {
+		return l.fullMethod
+	}
+	return otherValue
+}
+
+// rpcType returns a type of an RPC, as defined by go-grpc-prometheus.
+func rpcType(stat *stats.Begin) string {
+	switch {
+	case stat.IsClientStream && stat.IsServerStream:
+		return rpcTypeBidiStream
+	case stat.IsClientStream:
+		return rpcTypeClientStream
+	case stat.IsServerStream:
+		return rpcTypeServerStream
+	default:
+		return rpcTypeUnary
+	}
+}
+
+// payloadMessageType returns the full protobuf name of a message carried by a given payload.
+func payloadMessageType(stat stats.RPCStats) string {
+	switch pay := stat.(type) {
+	case *stats.InPayload:
+		return messageType(pay.Payload)
+	case *stats.OutPayload:
+		return messageType(pay.Payload)
+	default:
+		return notAvailable
+	}
 }
 
 type connTagLabels struct {
@@ -33,4 +147,151 @@ type connTagLabels struct {
 // That way caller gets the ability to modify the way labels are assembled.
 type HandleRPCLabelFunc func(context.Context, stats.RPCStats) []string
 
+// optionalLabels is a set of labels that are not a part of the default label set of a metric.
+// Each of them has to be enabled explicitly, both for a collector and for a stats handler.
+type optionalLabels uint8
+
+const (
+	optionalLabelCompression optionalLabels = 1 << iota
+	optionalLabelType
+	optionalLabelMessageType
+)
+
+// names returns label names of the given set in a deterministic order.
+func (l optionalLabels) names() []string {
+	var names []string
+	if l&optionalLabelCompression != 0 {
+		names = append(names, labelCompression)
+	}
+	if l&optionalLabelType != 0 {
+		names = append(names, labelType)
+	}
+	if l&optionalLabelMessageType != 0 {
+		names = append(names, labelMessageType)
+	}
+	return names
+}
+
+// values returns label values of the given set in the same order as names does.
+func (l optionalLabels) values(ctx context.Context, stat stats.RPCStats) []string {
+	var values []string
+	if l&optionalLabelCompression != 0 {
+		values = append(values, ctx.Value(tagRPCKey).(*rpcTagLabels).compression(stat))
+	}
+	if l&optionalLabelType != 0 {
+		values = append(values, ctx.Value(tagRPCKey).(*rpcTagLabels).rpcType)
+	}
+	if l&optionalLabelMessageType != 0 {
+		values = append(values, payloadMessageType(stat))
+	}
+	return values
+}
+
 type TagRPCLabelFunc func(context.Context, *stats.RPCTagInfo) context.Context
+
+// CustomLabelsFunc returns values of custom labels of an RPC, see WithCustomLabels.
+// Values have to be returned in the same order as names are passed to WithCustomLabels.
+type CustomLabelsFunc func(context.Context, *stats.RPCTagInfo) []string
+
+// fitLabelValues returns exactly n label values, missing ones are reported as not available and excess ones are dropped.
+// A mismatch would make a collector panic on every RPC, hence the check.
+func fitLabelValues(values []string, n int) []string {
+	if len(values) == n {
+		return values
+	}
+	res := make([]string, n)
+	for i := range res {
+		if i < len(values) {
+			res[i] = values[i]
+		} else {
+			res[i] = notAvailable
+		}
+	}
+	return res
+}
+
+// MetadataLabel maps incoming metadata of a given key to a label, see WithMetadataLabels.
+type MetadataLabel struct {
+	// Key of incoming metadata, e.g. x-tenant-tier.
+	Key string
+	// Name of a label, e.g. tenant_tier.
+	Name string
+	// Values reported as they are, any other value is reported as other.
+	// Keeping the list short keeps the number of series bounded.
+	Values []string
+}
+
+// metadataLabel is a MetadataLabel prepared for lookups.
+type metadataLabel struct {
+	key     string
+	allowed map[string]struct{}
+}
+
+func newMetadataLabels(labels []MetadataLabel) []metadataLabel {
+	res := make([]metadataLabel, 0, len(labels))
+	for _, l := range labels {
+		ml := metadataLabel{
+			key:     strings.ToLower(l.Key),
+			allowed: make(map[string]struct{}, len(l.Values)),
+		}
+		for _, v := range l.Values {
+			ml.allowed[v] = struct{}{}
+		}
+		res = append(res, ml)
+	}
+	return res
+}
+
+// metadataLabelValues reads values of metadata labels from incoming metadata, the same way userAgentOnServerSide does.
+// A missing key is reported as not available, and a value that is not allowed, or more than one value, as other.
+func metadataLabelValues(ctx context.Context, labels []metadataLabel) []string {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	values := make([]string, 0, len(labels))
+	for _, l := range labels {
+		vals, ok := md[l.key]
+		switch {
+		case !ok || len(vals) == 0:
+			values = append(values, notAvailable)
+		case len(vals) > 1:
+			values = append(values, otherValue)
+		default:
+			if _, ok := l.allowed[vals[0]]; ok {
+				values = append(values, vals[0])
+			} else {
+				values = append(values, otherValue)
+			}
+		}
+	}
+	return values
+}
+
+// ExemplarFunc type represents a function signature that can be passed into a stats handler to attach exemplars to observations.
+// It is given the context of an RPC, so it can read e.g. a trace ID of a span or a request ID from incoming metadata.
+// Returning no labels means no exemplar.
+type ExemplarFunc func(context.Context) prometheus.Labels
+
+// validExemplar reports whether given labels can be used as an exemplar.
+// Invalid exemplars make client_golang panic, hence the check.
+func validExemplar(lbs prometheus.Labels) bool {
+	var runes int
+	for name, value := range lbs {
+		if !validLabelName(name) || !utf8.ValidString(value) {
+			return false
+		}
+		runes += utf8.RuneCountInString(name) + utf8.RuneCountInString(value)
+	}
+	return runes <= prometheus.ExemplarMaxRunes
+}
+
+func validLabelName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '_' || r >= '0' && r <= '9' && i > 0) {
+			return false
+		}
+	}
+	return true
+}
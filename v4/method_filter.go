@@ -0,0 +1,79 @@
+package promgrpc
+
+import (
+	"strings"
+)
+
+// MethodFilterFunc reports whether an RPC of a given full method name (e.g. /package.Service/Method) should be measured.
+// See StatsHandlerWithMethodFilter.
+type MethodFilterFunc func(fullMethod string) bool
+
+// MethodAllowlist returns a MethodFilterFunc that accepts only methods matching at least one of given glob patterns.
+// See MethodDenylist for the pattern syntax.
+func MethodAllowlist(patterns ...string) MethodFilterFunc {
+	patterns = trimMethodPatterns(patterns)
+
+	return func(fullMethod string) bool {
+		return matchMethod(patterns, fullMethod)
+	}
+}
+
+// MethodDenylist returns a MethodFilterFunc that rejects methods matching any of given glob patterns, e.g.
+//
+//	MethodDenylist("grpc.health.v1.Health/*", "grpc.reflection.*")
+//
+// A star matches any sequence of characters, including dots and slashes, and a question mark matches a single character.
+// The leading slash is optional.
+func MethodDenylist(patterns ...string) MethodFilterFunc {
+	patterns = trimMethodPatterns(patterns)
+
+	return func(fullMethod string) bool {
+		return !matchMethod(patterns, fullMethod)
+	}
+}
+
+func trimMethodPatterns(patterns []string) []string {
+	trimmed := make([]string, 0, len(patterns))
+	for _, p := range patterns {
+		trimmed = append(trimmed, strings.TrimPrefix(p, "/"))
+	}
+	return trimmed
+}
+
+func matchMethod(patterns []string, fullMethod string) bool {
+	name := strings.TrimPrefix(fullMethod, "/")
+	for _, p := range patterns {
+		if matchGlob(p, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob reports whether a name matches a pattern, where a star matches any sequence of characters and a question mark a single one.
+// Backtracking is limited to the most recent star, which is enough since a star matches slashes as well.
+func matchGlob(pattern, name string) bool {
+	var (
+		p, n        int
+		star, starN = -1, 0
+	)
+	for n < len(name) {
+		switch {
+		case p < len(pattern) && (pattern[p] == '?' || pattern[p] == name[n]):
+			p++
+			n++
+		case p < len(pattern) && pattern[p] == '*':
+			star, starN = p, n
+			p++
+		case star >= 0:
+			starN++
+			p, n = star+1, starN
+		default:
+			return false
+		}
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
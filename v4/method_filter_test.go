@@ -0,0 +1,179 @@
+package promgrpc_test
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+)
+
+func TestMethodDenylist(t *testing.T) {
+	filter := promgrpc.MethodDenylist("grpc.health.v1.Health/*", "/grpc.reflection.*", "service/Get?")
+
+	cases := map[string]bool{
+		"/grpc.health.v1.Health/Check":                                   false,
+		"/grpc.health.v1.Health/Watch":                                   false,
+		"/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo": false,
+		"/service/GetX":                false,
+		"/service/Get":                 true,
+		"/service/GetXY":               true,
+		"/service/Method":              true,
+		"/grpc.health.v2.Health/Check": true,
+	}
+	for method, expected := range cases {
+		if got := filter(method); got != expected {
+			t.Errorf("%s: expected %t, but got %t", method, expected, got)
+		}
+	}
+}
+
+func TestMethodAllowlist(t *testing.T) {
+	filter := promgrpc.MethodAllowlist("/package.*/*")
+
+	cases := map[string]bool{
+		"/package.Service/Method":      true,
+		"/package.v1.Service/Method":   true,
+		"/other.Service/Method":        false,
+		"/grpc.health.v1.Health/Check": false,
+	}
+	for method, expected := range cases {
+		if got := filter(method); got != expected {
+			t.Errorf("%s: expected %t, but got %t", method, expected, got)
+		}
+	}
+}
+
+func TestStatsHandler_methodFilter(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	ssh := promgrpc.ServerStatsHandler(promgrpc.StatsHandlerWithMethodFilter(promgrpc.MethodDenylist("grpc.health.v1.Health/*")))
+	csh := promgrpc.ClientStatsHandler(promgrpc.StatsHandlerWithMethodFilter(promgrpc.MethodDenylist("grpc.health.v1.Health/*")))
+
+	for _, method := range []string{"/grpc.health.v1.Health/Check", "/service/Method"} {
+		sctx := ssh.TagRPC(ctx, &stats.RPCTagInfo{FullMethodName: method})
+		ssh.HandleRPC(sctx, &stats.Begin{})
+		ssh.HandleRPC(sctx, &stats.End{})
+
+		// An outgoing health check made while handling an incoming RPC.
+		cctx := csh.TagRPC(sctx, &stats.RPCTagInfo{FullMethodName: "/grpc.health.v1.Health/Check"})
+		csh.HandleRPC(cctx, &stats.Begin{Client: true})
+		csh.HandleRPC(cctx, &stats.End{Client: true})
+	}
+
+	if n := testutil.CollectAndCount(ssh, "grpc_server_requests_received_total", "grpc_server_responses_sent_total"); n != 2 {
+		t.Fatalf("unexpected number of server series, expected 2 but got %d", n)
+	}
+	if n := testutil.CollectAndCount(csh, "grpc_client_requests_sent_total", "grpc_client_responses_received_total"); n != 0 {
+		t.Fatalf("unexpected number of client series, expected 0 but got %d", n)
+	}
+}
+
+func TestStatsHandler_methodFilter_multipleCoordinators(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	all := promgrpc.ServerStatsHandler()
+	filtered := promgrpc.ServerStatsHandler(promgrpc.StatsHandlerWithMethodFilter(promgrpc.MethodDenylist("grpc.health.v1.Health/*")))
+
+	for _, coordinators := range [][]*promgrpc.StatsHandler{{all, filtered}, {filtered, all}} {
+		// Both coordinators installed on the same server, in either order.
+		sctx := ctx
+		for _, c := range coordinators {
+			sctx = c.TagRPC(sctx, &stats.RPCTagInfo{FullMethodName: "/grpc.health.v1.Health/Check"})
+		}
+		for _, sts := range []stats.RPCStats{&stats.Begin{}, &stats.End{}} {
+			for _, c := range coordinators {
+				c.HandleRPC(sctx, sts)
+			}
+		}
+	}
+
+	if n := testutil.CollectAndCount(all, "grpc_server_requests_received_total"); n != 1 {
+		t.Fatalf("unexpected number of series, expected 1 but got %d", n)
+	}
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(all)
+	mf, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range mf {
+		if m.GetName() == "grpc_server_requests_received_total" {
+			if n := m.GetMetric()[0].GetCounter().GetValue(); n != 2 {
+				t.Fatalf("unexpected number of requests, expected 2 but got %g", n)
+			}
+		}
+	}
+	if n := testutil.CollectAndCount(filtered, "grpc_server_requests_received_total", "grpc_server_responses_sent_total"); n != 0 {
+		t.Fatalf("unexpected number of series, expected 0 but got %d", n)
+	}
+}
+
+func TestStatsHandler_methodFilter_connections(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.NewStatsHandlerWithOptions([]promgrpc.StatsHandlerCollector{
+		promgrpc.NewServerIdleConnectionsStatsHandler(promgrpc.NewServerIdleConnectionsGaugeVec()),
+		promgrpc.NewServerRequestsPerConnectionStatsHandler(promgrpc.NewServerRequestsPerConnectionHistogramVec()),
+		promgrpc.NewServerMaxConcurrentStreamsStatsHandler(promgrpc.NewServerMaxConcurrentStreamsGaugeVec(), 0),
+		promgrpc.NewServerRequestsTotalStatsHandler(promgrpc.NewServerRequestsTotalCounterVec()),
+	}, promgrpc.StatsHandlerWithMethodFilter(promgrpc.MethodDenylist("grpc.health.v1.Health/*")))
+
+	ctx = metadata.NewIncomingContext(ctx, metadata.MD{"user-agent": []string{"fake-user-agent"}})
+	connCtx := h.TagConn(ctx, &stats.ConnTagInfo{
+		LocalAddr:  &net.TCPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 80},
+		RemoteAddr: &net.TCPAddr{IP: net.IPv4(4, 3, 2, 1), Port: 111},
+	})
+	h.HandleConn(connCtx, &stats.ConnBegin{})
+
+	// A long-lived stream that is not measured.
+	rpcCtx := h.TagRPC(connCtx, &stats.RPCTagInfo{FullMethodName: "/grpc.health.v1.Health/Watch"})
+	h.HandleRPC(rpcCtx, &stats.Begin{})
+
+	if n := testutil.CollectAndCount(h, "grpc_server_idle_connections"); n != 0 {
+		t.Fatalf("connection reported as idle, expected 0 series but got %d", n)
+	}
+	expected := `
+		# HELP grpc_server_max_concurrent_streams Highest number of RPCs concurrently active on a single connection, currently and since the start, next to the configured limit.
+		# TYPE grpc_server_max_concurrent_streams gauge
+		grpc_server_max_concurrent_streams{grpc_source="observed"} 1
+		grpc_server_max_concurrent_streams{grpc_source="peak"} 1
+	`
+	if err := testutil.CollectAndCompare(h, strings.NewReader(expected), "grpc_server_max_concurrent_streams"); err != nil {
+		t.Fatal(err)
+	}
+
+	h.HandleRPC(rpcCtx, &stats.End{})
+	h.HandleConn(connCtx, &stats.ConnEnd{})
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(h)
+	mf, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found bool
+	for _, m := range mf {
+		switch m.GetName() {
+		case "grpc_server_requests_per_connection_histogram":
+			found = true
+			if n := m.GetMetric()[0].GetHistogram().GetSampleSum(); n != 1 {
+				t.Fatalf("unexpected number of requests per connection, expected 1 but got %g", n)
+			}
+		case "grpc_server_requests_received_total":
+			t.Fatal("skipped RPC is not expected to be counted")
+		}
+	}
+	if !found {
+		t.Fatal("requests per connection not observed")
+	}
+}
@@ -0,0 +1,57 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewClientAttemptDurationHistogramVec allocates a new Prometheus HistogramVec for the client and given set of options.
+// It follows naming conventions of gRPC proposal A66.
+func NewClientAttemptDurationHistogramVec(opts ...CollectorOption) *prometheus.HistogramVec {
+	labels := []string{
+		labelMethod,
+		labelStatus,
+		labelTarget,
+	}
+	return newLatencyHistogramVec("client", "attempt_duration_seconds", "End-to-end time taken to complete a client call attempt.", labels, opts...)
+}
+
+// ClientAttemptDurationStatsHandler is responsible for observing how long attempts of outgoing calls take, following gRPC proposal A66 (grpc.client.attempt.duration).
+type ClientAttemptDurationStatsHandler struct {
+	baseStatsHandler
+	vec prometheus.ObserverVec
+}
+
+// NewClientAttemptDurationStatsHandler ...
+func NewClientAttemptDurationStatsHandler(vec prometheus.ObserverVec, opts ...StatsHandlerOption) *ClientAttemptDurationStatsHandler {
+	h := &ClientAttemptDurationStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+			options: statsHandlerOptions{
+				handleRPCLabelFn: clientAttemptDurationLabels,
+			},
+		},
+		vec: vec,
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ClientAttemptDurationStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if pay, ok := stat.(*stats.End); ok && stat.IsClient() {
+		h.observe(ctx, h.vec, h.labelValues(ctx, stat), pay.EndTime.Sub(pay.BeginTime).Seconds())
+	}
+}
+
+func clientAttemptDurationLabels(ctx context.Context, stat stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	return []string{
+		tag.fullMethod,
+		statusCodeName(stat.(*stats.End).Error),
+		tag.target,
+	}
+}
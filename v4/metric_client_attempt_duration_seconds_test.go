@@ -0,0 +1,140 @@
+package promgrpc_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+)
+
+func TestNewClientAttemptDurationStatsHandler(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.NewStatsHandler(promgrpc.NewClientAttemptDurationStatsHandler(promgrpc.NewClientAttemptDurationHistogramVec()))
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+		FullMethodName: "/package.Service/Method",
+		FailFast:       true,
+	})
+	begin := time.Now()
+	h.HandleRPC(ctx, &stats.End{
+		Client:    true,
+		BeginTime: begin,
+		EndTime:   begin.Add(250 * time.Millisecond),
+	})
+	h.HandleRPC(ctx, &stats.End{
+		Client:    true,
+		BeginTime: begin,
+		EndTime:   begin.Add(3 * time.Second),
+		Error:     status.Error(codes.DeadlineExceeded, "deadline exceeded"),
+	})
+	h.HandleRPC(ctx, &stats.End{
+		BeginTime: begin,
+		EndTime:   begin.Add(time.Second),
+	})
+
+	const metadata = `
+		# HELP grpc_client_attempt_duration_seconds End-to-end time taken to complete a client call attempt.
+        # TYPE grpc_client_attempt_duration_seconds histogram
+	`
+	expected := `
+		grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="DEADLINE_EXCEEDED",grpc_target="n/a",le="0"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="DEADLINE_EXCEEDED",grpc_target="n/a",le="1e-05"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="DEADLINE_EXCEEDED",grpc_target="n/a",le="5e-05"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="DEADLINE_EXCEEDED",grpc_target="n/a",le="0.0001"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="DEADLINE_EXCEEDED",grpc_target="n/a",le="0.0003"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="DEADLINE_EXCEEDED",grpc_target="n/a",le="0.0006"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="DEADLINE_EXCEEDED",grpc_target="n/a",le="0.0008"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="DEADLINE_EXCEEDED",grpc_target="n/a",le="0.001"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="DEADLINE_EXCEEDED",grpc_target="n/a",le="0.002"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="DEADLINE_EXCEEDED",grpc_target="n/a",le="0.003"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="DEADLINE_EXCEEDED",grpc_target="n/a",le="0.004"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="DEADLINE_EXCEEDED",grpc_target="n/a",le="0.005"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="DEADLINE_EXCEEDED",grpc_target="n/a",le="0.006"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="DEADLINE_EXCEEDED",grpc_target="n/a",le="0.008"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="DEADLINE_EXCEEDED",grpc_target="n/a",le="0.01"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="DEADLINE_EXCEEDED",grpc_target="n/a",le="0.013"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="DEADLINE_EXCEEDED",grpc_target="n/a",le="0.016"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="DEADLINE_EXCEEDED",grpc_target="n/a",le="0.02"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="DEADLINE_EXCEEDED",grpc_target="n/a",le="0.025"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="DEADLINE_EXCEEDED",grpc_target="n/a",le="0.03"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="DEADLINE_EXCEEDED",grpc_target="n/a",le="0.04"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="DEADLINE_EXCEEDED",grpc_target="n/a",le="0.05"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="DEADLINE_EXCEEDED",grpc_target="n/a",le="0.065"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="DEADLINE_EXCEEDED",grpc_target="n/a",le="0.08"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="DEADLINE_EXCEEDED",grpc_target="n/a",le="0.1"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="DEADLINE_EXCEEDED",grpc_target="n/a",le="0.13"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="DEADLINE_EXCEEDED",grpc_target="n/a",le="0.16"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="DEADLINE_EXCEEDED",grpc_target="n/a",le="0.2"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="DEADLINE_EXCEEDED",grpc_target="n/a",le="0.25"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="DEADLINE_EXCEEDED",grpc_target="n/a",le="0.3"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="DEADLINE_EXCEEDED",grpc_target="n/a",le="0.4"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="DEADLINE_EXCEEDED",grpc_target="n/a",le="0.5"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="DEADLINE_EXCEEDED",grpc_target="n/a",le="0.65"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="DEADLINE_EXCEEDED",grpc_target="n/a",le="0.8"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="DEADLINE_EXCEEDED",grpc_target="n/a",le="1"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="DEADLINE_EXCEEDED",grpc_target="n/a",le="2"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="DEADLINE_EXCEEDED",grpc_target="n/a",le="5"} 1
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="DEADLINE_EXCEEDED",grpc_target="n/a",le="10"} 1
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="DEADLINE_EXCEEDED",grpc_target="n/a",le="20"} 1
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="DEADLINE_EXCEEDED",grpc_target="n/a",le="50"} 1
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="DEADLINE_EXCEEDED",grpc_target="n/a",le="100"} 1
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="DEADLINE_EXCEEDED",grpc_target="n/a",le="+Inf"} 1
+        grpc_client_attempt_duration_seconds_sum{grpc_method="package.Service/Method",grpc_status="DEADLINE_EXCEEDED",grpc_target="n/a"} 3
+        grpc_client_attempt_duration_seconds_count{grpc_method="package.Service/Method",grpc_status="DEADLINE_EXCEEDED",grpc_target="n/a"} 1
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="OK",grpc_target="n/a",le="0"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="OK",grpc_target="n/a",le="1e-05"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="OK",grpc_target="n/a",le="5e-05"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="OK",grpc_target="n/a",le="0.0001"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="OK",grpc_target="n/a",le="0.0003"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="OK",grpc_target="n/a",le="0.0006"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="OK",grpc_target="n/a",le="0.0008"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="OK",grpc_target="n/a",le="0.001"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="OK",grpc_target="n/a",le="0.002"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="OK",grpc_target="n/a",le="0.003"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="OK",grpc_target="n/a",le="0.004"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="OK",grpc_target="n/a",le="0.005"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="OK",grpc_target="n/a",le="0.006"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="OK",grpc_target="n/a",le="0.008"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="OK",grpc_target="n/a",le="0.01"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="OK",grpc_target="n/a",le="0.013"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="OK",grpc_target="n/a",le="0.016"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="OK",grpc_target="n/a",le="0.02"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="OK",grpc_target="n/a",le="0.025"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="OK",grpc_target="n/a",le="0.03"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="OK",grpc_target="n/a",le="0.04"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="OK",grpc_target="n/a",le="0.05"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="OK",grpc_target="n/a",le="0.065"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="OK",grpc_target="n/a",le="0.08"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="OK",grpc_target="n/a",le="0.1"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="OK",grpc_target="n/a",le="0.13"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="OK",grpc_target="n/a",le="0.16"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="OK",grpc_target="n/a",le="0.2"} 0
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="OK",grpc_target="n/a",le="0.25"} 1
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="OK",grpc_target="n/a",le="0.3"} 1
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="OK",grpc_target="n/a",le="0.4"} 1
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="OK",grpc_target="n/a",le="0.5"} 1
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="OK",grpc_target="n/a",le="0.65"} 1
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="OK",grpc_target="n/a",le="0.8"} 1
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="OK",grpc_target="n/a",le="1"} 1
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="OK",grpc_target="n/a",le="2"} 1
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="OK",grpc_target="n/a",le="5"} 1
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="OK",grpc_target="n/a",le="10"} 1
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="OK",grpc_target="n/a",le="20"} 1
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="OK",grpc_target="n/a",le="50"} 1
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="OK",grpc_target="n/a",le="100"} 1
+        grpc_client_attempt_duration_seconds_bucket{grpc_method="package.Service/Method",grpc_status="OK",grpc_target="n/a",le="+Inf"} 1
+        grpc_client_attempt_duration_seconds_sum{grpc_method="package.Service/Method",grpc_status="OK",grpc_target="n/a"} 0.25
+        grpc_client_attempt_duration_seconds_count{grpc_method="package.Service/Method",grpc_status="OK",grpc_target="n/a"} 1
+	`
+
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_client_attempt_duration_seconds"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,79 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewClientAttemptRcvdTotalCompressedMessageSizeHistogramVec allocates a new Prometheus HistogramVec for the client and given set of options.
+// It follows naming conventions of gRPC proposal A66.
+func NewClientAttemptRcvdTotalCompressedMessageSizeHistogramVec(opts ...CollectorOption) *prometheus.HistogramVec {
+	labels := []string{
+		labelMethod,
+		labelStatus,
+		labelTarget,
+	}
+	return newTotalCompressedMessageSizeHistogramVec("client", "attempt_rcvd_total_compressed_message_size_bytes", "Compressed message bytes received per client call attempt.", labels, opts...)
+}
+
+// ClientAttemptRcvdTotalCompressedMessageSizeStatsHandler is responsible for observing the total size of compressed messages received within an attempt of an outgoing call,
+// following gRPC proposal A66 (grpc.client.attempt.rcvd_total_compressed_message_size).
+// Metadata and framing are not included.
+type ClientAttemptRcvdTotalCompressedMessageSizeStatsHandler struct {
+	baseStatsHandler
+	vec prometheus.ObserverVec
+}
+
+// NewClientAttemptRcvdTotalCompressedMessageSizeStatsHandler ...
+func NewClientAttemptRcvdTotalCompressedMessageSizeStatsHandler(vec prometheus.ObserverVec, opts ...StatsHandlerOption) *ClientAttemptRcvdTotalCompressedMessageSizeStatsHandler {
+	h := &ClientAttemptRcvdTotalCompressedMessageSizeStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+			options: statsHandlerOptions{
+				handleRPCLabelFn: clientAttemptRcvdTotalCompressedMessageSizeLabels,
+			},
+		},
+		vec: vec,
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// TagRPC implements stats Handler interface.
+func (h *ClientAttemptRcvdTotalCompressedMessageSizeStatsHandler) TagRPC(ctx context.Context, inf *stats.RPCTagInfo) context.Context {
+	ctx = h.baseStatsHandler.TagRPC(ctx, inf)
+	ctx = context.WithValue(ctx, clientAttemptRcvdTotalCompressedMessageSizeKey{}, &requestTotalMark{})
+	return ctx
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ClientAttemptRcvdTotalCompressedMessageSizeStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if !stat.IsClient() {
+		return
+	}
+	mrk, ok := ctx.Value(clientAttemptRcvdTotalCompressedMessageSizeKey{}).(*requestTotalMark)
+	if !ok {
+		return
+	}
+
+	switch pay := stat.(type) {
+	case *stats.InPayload:
+		mrk.add(int64(pay.CompressedLength))
+	case *stats.End:
+		h.observe(ctx, h.vec, h.labelValues(ctx, stat), float64(mrk.load()))
+	}
+}
+
+func clientAttemptRcvdTotalCompressedMessageSizeLabels(ctx context.Context, stat stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	return []string{
+		tag.fullMethod,
+		statusCodeName(stat.(*stats.End).Error),
+		tag.target,
+	}
+}
+
+type clientAttemptRcvdTotalCompressedMessageSizeKey struct{}
@@ -0,0 +1,79 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewClientAttemptSentTotalCompressedMessageSizeHistogramVec allocates a new Prometheus HistogramVec for the client and given set of options.
+// It follows naming conventions of gRPC proposal A66.
+func NewClientAttemptSentTotalCompressedMessageSizeHistogramVec(opts ...CollectorOption) *prometheus.HistogramVec {
+	labels := []string{
+		labelMethod,
+		labelStatus,
+		labelTarget,
+	}
+	return newTotalCompressedMessageSizeHistogramVec("client", "attempt_sent_total_compressed_message_size_bytes", "Compressed message bytes sent per client call attempt.", labels, opts...)
+}
+
+// ClientAttemptSentTotalCompressedMessageSizeStatsHandler is responsible for observing the total size of compressed messages sent within an attempt of an outgoing call,
+// following gRPC proposal A66 (grpc.client.attempt.sent_total_compressed_message_size).
+// Metadata and framing are not included.
+type ClientAttemptSentTotalCompressedMessageSizeStatsHandler struct {
+	baseStatsHandler
+	vec prometheus.ObserverVec
+}
+
+// NewClientAttemptSentTotalCompressedMessageSizeStatsHandler ...
+func NewClientAttemptSentTotalCompressedMessageSizeStatsHandler(vec prometheus.ObserverVec, opts ...StatsHandlerOption) *ClientAttemptSentTotalCompressedMessageSizeStatsHandler {
+	h := &ClientAttemptSentTotalCompressedMessageSizeStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+			options: statsHandlerOptions{
+				handleRPCLabelFn: clientAttemptSentTotalCompressedMessageSizeLabels,
+			},
+		},
+		vec: vec,
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// TagRPC implements stats Handler interface.
+func (h *ClientAttemptSentTotalCompressedMessageSizeStatsHandler) TagRPC(ctx context.Context, inf *stats.RPCTagInfo) context.Context {
+	ctx = h.baseStatsHandler.TagRPC(ctx, inf)
+	ctx = context.WithValue(ctx, clientAttemptSentTotalCompressedMessageSizeKey{}, &requestTotalMark{})
+	return ctx
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ClientAttemptSentTotalCompressedMessageSizeStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if !stat.IsClient() {
+		return
+	}
+	mrk, ok := ctx.Value(clientAttemptSentTotalCompressedMessageSizeKey{}).(*requestTotalMark)
+	if !ok {
+		return
+	}
+
+	switch pay := stat.(type) {
+	case *stats.OutPayload:
+		mrk.add(int64(pay.CompressedLength))
+	case *stats.End:
+		h.observe(ctx, h.vec, h.labelValues(ctx, stat), float64(mrk.load()))
+	}
+}
+
+func clientAttemptSentTotalCompressedMessageSizeLabels(ctx context.Context, stat stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	return []string{
+		tag.fullMethod,
+		statusCodeName(stat.(*stats.End).Error),
+		tag.target,
+	}
+}
+
+type clientAttemptSentTotalCompressedMessageSizeKey struct{}
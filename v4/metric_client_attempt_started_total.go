@@ -0,0 +1,56 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewClientAttemptStartedCounterVec allocates a new Prometheus CounterVec for the client and given set of options.
+// It follows naming conventions of gRPC proposal A66.
+func NewClientAttemptStartedCounterVec(opts ...CollectorOption) *prometheus.CounterVec {
+	labels := []string{
+		labelMethod,
+		labelTarget,
+	}
+	return newStartedTotalCounterVec("client", "attempt_started_total", "Number of client call attempts started.", labels, opts...)
+}
+
+// ClientAttemptStartedStatsHandler is responsible for counting attempts of outgoing calls as they begin, following gRPC proposal A66 (grpc.client.attempt.started).
+// Each retry or hedged request is a separate attempt.
+type ClientAttemptStartedStatsHandler struct {
+	baseStatsHandler
+	vec *prometheus.CounterVec
+}
+
+// NewClientAttemptStartedStatsHandler ...
+func NewClientAttemptStartedStatsHandler(vec *prometheus.CounterVec, opts ...StatsHandlerOption) *ClientAttemptStartedStatsHandler {
+	h := &ClientAttemptStartedStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+			options: statsHandlerOptions{
+				handleRPCLabelFn: clientAttemptStartedLabels,
+			},
+		},
+		vec: vec,
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ClientAttemptStartedStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if _, ok := stat.(*stats.Begin); ok && stat.IsClient() {
+		h.inc(ctx, h.vec.WithLabelValues(h.labelValues(ctx, stat)...))
+	}
+}
+
+func clientAttemptStartedLabels(ctx context.Context, _ stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	return []string{
+		tag.fullMethod,
+		tag.target,
+	}
+}
@@ -0,0 +1,43 @@
+package promgrpc_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/stats"
+)
+
+func TestNewClientAttemptStartedStatsHandler(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.NewStatsHandler(promgrpc.NewClientAttemptStartedStatsHandler(promgrpc.NewClientAttemptStartedCounterVec()))
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+		FullMethodName: "/package.Service/Method",
+		FailFast:       true,
+	})
+	h.HandleRPC(ctx, &stats.Begin{
+		Client: true,
+	})
+	h.HandleRPC(ctx, &stats.Begin{
+		Client:                    true,
+		IsTransparentRetryAttempt: true,
+	})
+	h.HandleRPC(ctx, &stats.Begin{})
+
+	const metadata = `
+		# HELP grpc_client_attempt_started_total Number of client call attempts started.
+        # TYPE grpc_client_attempt_started_total counter
+	`
+	expected := `
+		grpc_client_attempt_started_total{grpc_method="package.Service/Method",grpc_target="n/a"} 2
+	`
+
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_client_attempt_started_total"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,70 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/piotrkowalczuk/promgrpc/v4/internal/useragent"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+)
+
+// NewClientAttemptsPerCallHistogramVec allocates a new Prometheus HistogramVec for the client and given set of options.
+func NewClientAttemptsPerCallHistogramVec(opts ...CollectorOption) *prometheus.HistogramVec {
+	labels := []string{
+		labelCode,
+		labelIsFailFast,
+		labelMethod,
+		labelService,
+		labelClientUserAgent,
+	}
+	return newAttemptsPerCallHistogramVec("client", labels, opts...)
+}
+
+// ClientAttemptsPerCallStatsHandler is responsible for observing the number of attempts made within a single call, including transparent retries.
+// It is observed once the call ends, with the code the call ended with.
+// It requires StatsHandler.UnaryClientInterceptor and StatsHandler.StreamClientInterceptor to be installed.
+type ClientAttemptsPerCallStatsHandler struct {
+	baseStatsHandler
+	uas useragent.Store
+	vec prometheus.ObserverVec
+}
+
+// NewClientAttemptsPerCallStatsHandler ...
+func NewClientAttemptsPerCallStatsHandler(vec prometheus.ObserverVec, opts ...StatsHandlerOption) *ClientAttemptsPerCallStatsHandler {
+	h := &ClientAttemptsPerCallStatsHandler{
+		vec: vec,
+	}
+	h.baseStatsHandler = baseStatsHandler{
+		collector: vec,
+		options: statsHandlerOptions{
+			handleRPCLabelFn: h.labels,
+		},
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ClientAttemptsPerCallStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if pay, ok := stat.(*stats.OutHeader); ok {
+		_ = h.uas.ClientSide(ctx, pay)
+	}
+}
+
+func (h *ClientAttemptsPerCallStatsHandler) handleCall(ctx context.Context, attempts int64, stat *stats.End) {
+	h.observe(ctx, h.vec, h.labelValues(ctx, stat), float64(attempts))
+}
+
+func (h *ClientAttemptsPerCallStatsHandler) labels(ctx context.Context, stat stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	return []string{
+		status.Code(stat.(*stats.End).Error).String(),
+		tag.isFailFast,
+		tag.method,
+		tag.service,
+		h.uas.ClientSide(ctx, stat),
+	}
+}
@@ -0,0 +1,206 @@
+package promgrpc_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+)
+
+func TestNewClientAttemptsPerCallStatsHandler(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.NewStatsHandler(promgrpc.NewClientAttemptsPerCallStatsHandler(promgrpc.NewClientAttemptsPerCallHistogramVec()))
+	interceptor := h.UnaryClientInterceptor()
+
+	invoker := func(errs ...error) grpc.UnaryInvoker {
+		return func(ctx context.Context, method string, _, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+			var err error
+			for _, err = range errs {
+				ctx := h.TagRPC(ctx, &stats.RPCTagInfo{
+					FullMethodName: method,
+					FailFast:       true,
+				})
+				h.HandleRPC(ctx, &stats.Begin{Client: true})
+				h.HandleRPC(ctx, &stats.OutHeader{
+					Client: true,
+					Header: metadata.MD{"user-agent": []string{"fake-user-agent"}},
+				})
+				h.HandleRPC(ctx, &stats.End{Client: true, Error: err})
+			}
+			return err
+		}
+	}
+	unavailable := status.Error(codes.Unavailable, "unavailable")
+
+	if err := interceptor(ctx, "/service/Method", nil, nil, nil, invoker(nil)); err != nil {
+		t.Fatal(err)
+	}
+	if err := interceptor(ctx, "/service/Method", nil, nil, nil, invoker(unavailable, unavailable, nil)); err != nil {
+		t.Fatal(err)
+	}
+	if err := interceptor(ctx, "/service/Method", nil, nil, nil, invoker(unavailable, unavailable)); status.Code(err) != codes.Unavailable {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	const metadata = `
+		# HELP grpc_client_attempts_per_call_histogram Number of attempts made within a single call.
+        # TYPE grpc_client_attempts_per_call_histogram histogram
+	`
+	expected := `
+		grpc_client_attempts_per_call_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="1"} 1
+        grpc_client_attempts_per_call_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="2"} 1
+        grpc_client_attempts_per_call_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="3"} 2
+        grpc_client_attempts_per_call_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="4"} 2
+        grpc_client_attempts_per_call_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="5"} 2
+        grpc_client_attempts_per_call_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="10"} 2
+        grpc_client_attempts_per_call_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="+Inf"} 2
+        grpc_client_attempts_per_call_histogram_sum{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service"} 4
+        grpc_client_attempts_per_call_histogram_count{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service"} 2
+        grpc_client_attempts_per_call_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="Unavailable",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="1"} 0
+        grpc_client_attempts_per_call_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="Unavailable",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="2"} 1
+        grpc_client_attempts_per_call_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="Unavailable",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="3"} 1
+        grpc_client_attempts_per_call_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="Unavailable",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="4"} 1
+        grpc_client_attempts_per_call_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="Unavailable",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="5"} 1
+        grpc_client_attempts_per_call_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="Unavailable",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="10"} 1
+        grpc_client_attempts_per_call_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="Unavailable",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="+Inf"} 1
+        grpc_client_attempts_per_call_histogram_sum{grpc_client_user_agent="fake-user-agent",grpc_code="Unavailable",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service"} 2
+        grpc_client_attempts_per_call_histogram_count{grpc_client_user_agent="fake-user-agent",grpc_code="Unavailable",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service"} 1
+	`
+
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_client_attempts_per_call_histogram"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewClientAttemptsPerCallStatsHandler_multipleCoordinators(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.NewStatsHandler(promgrpc.NewClientAttemptsPerCallStatsHandler(promgrpc.NewClientAttemptsPerCallHistogramVec()))
+	// A coordinator installed next to the one that owns the interceptor, without an interceptor of its own.
+	other := promgrpc.ClientStatsHandler(promgrpc.WithCustomLabels(func(context.Context, *stats.RPCTagInfo) []string {
+		return []string{"acme"}
+	}, "tenant"))
+
+	invoker := func(ctx context.Context, method string, _, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+		for _, c := range []*promgrpc.StatsHandler{h, other} {
+			ctx = c.TagRPC(ctx, &stats.RPCTagInfo{FullMethodName: method})
+		}
+		for _, sts := range []stats.RPCStats{&stats.Begin{Client: true}, &stats.End{Client: true}} {
+			h.HandleRPC(ctx, sts)
+			other.HandleRPC(ctx, sts)
+		}
+		return nil
+	}
+	if err := h.UnaryClientInterceptor()(ctx, "/service/Method", nil, nil, nil, invoker); err != nil {
+		t.Fatal(err)
+	}
+
+	const metadata = `
+		# HELP grpc_client_attempts_per_call_histogram Number of attempts made within a single call.
+        # TYPE grpc_client_attempts_per_call_histogram histogram
+	`
+	expected := `
+		grpc_client_attempts_per_call_histogram_bucket{grpc_client_user_agent="n/a/y",grpc_code="OK",grpc_is_fail_fast="false",grpc_method="Method",grpc_service="service",le="1"} 1
+        grpc_client_attempts_per_call_histogram_bucket{grpc_client_user_agent="n/a/y",grpc_code="OK",grpc_is_fail_fast="false",grpc_method="Method",grpc_service="service",le="2"} 1
+        grpc_client_attempts_per_call_histogram_bucket{grpc_client_user_agent="n/a/y",grpc_code="OK",grpc_is_fail_fast="false",grpc_method="Method",grpc_service="service",le="3"} 1
+        grpc_client_attempts_per_call_histogram_bucket{grpc_client_user_agent="n/a/y",grpc_code="OK",grpc_is_fail_fast="false",grpc_method="Method",grpc_service="service",le="4"} 1
+        grpc_client_attempts_per_call_histogram_bucket{grpc_client_user_agent="n/a/y",grpc_code="OK",grpc_is_fail_fast="false",grpc_method="Method",grpc_service="service",le="5"} 1
+        grpc_client_attempts_per_call_histogram_bucket{grpc_client_user_agent="n/a/y",grpc_code="OK",grpc_is_fail_fast="false",grpc_method="Method",grpc_service="service",le="10"} 1
+        grpc_client_attempts_per_call_histogram_bucket{grpc_client_user_agent="n/a/y",grpc_code="OK",grpc_is_fail_fast="false",grpc_method="Method",grpc_service="service",le="+Inf"} 1
+        grpc_client_attempts_per_call_histogram_sum{grpc_client_user_agent="n/a/y",grpc_code="OK",grpc_is_fail_fast="false",grpc_method="Method",grpc_service="service"} 1
+        grpc_client_attempts_per_call_histogram_count{grpc_client_user_agent="n/a/y",grpc_code="OK",grpc_is_fail_fast="false",grpc_method="Method",grpc_service="service"} 1
+	`
+
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_client_attempts_per_call_histogram"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewClientAttemptsPerCallStatsHandler_unfinishedStream(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.NewStatsHandler(promgrpc.NewClientAttemptsPerCallStatsHandler(promgrpc.NewClientAttemptsPerCallHistogramVec()))
+	interceptor := h.StreamClientInterceptor()
+
+	streamer := func(sendErr error) grpc.Streamer {
+		return func(ctx context.Context, _ *grpc.StreamDesc, _ *grpc.ClientConn, method string, _ ...grpc.CallOption) (grpc.ClientStream, error) {
+			ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+				FullMethodName: method,
+				FailFast:       true,
+			})
+			h.HandleRPC(ctx, &stats.Begin{Client: true})
+			h.HandleRPC(ctx, &stats.OutHeader{
+				Client: true,
+				Header: metadata.MD{"user-agent": []string{"fake-user-agent"}},
+			})
+			return &fakeClientStream{sendErr: sendErr}, nil
+		}
+	}
+	desc := &grpc.StreamDesc{ClientStreams: true, ServerStreams: true}
+
+	// A stream abandoned by canceling its context, RecvMsg is never called.
+	callCtx, callCancel := context.WithCancel(ctx)
+	if _, err := interceptor(callCtx, desc, nil, "/service/Method", streamer(nil)); err != nil {
+		t.Fatal(err)
+	}
+	callCancel()
+
+	// A stream that fails to send a message.
+	cs, err := interceptor(ctx, desc, nil, "/service/Method", streamer(status.Error(codes.Unavailable, "unavailable")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.SendMsg(nil); status.Code(err) != codes.Unavailable {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The canceled call is reported asynchronously.
+	for testutil.CollectAndCount(h, "grpc_client_attempts_per_call_histogram") < 2 {
+		select {
+		case <-ctx.Done():
+			t.Fatal("canceled call is not reported")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	const metadata = `
+		# HELP grpc_client_attempts_per_call_histogram Number of attempts made within a single call.
+        # TYPE grpc_client_attempts_per_call_histogram histogram
+	`
+	expected := `
+		grpc_client_attempts_per_call_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="Canceled",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="1"} 1
+        grpc_client_attempts_per_call_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="Canceled",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="2"} 1
+        grpc_client_attempts_per_call_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="Canceled",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="3"} 1
+        grpc_client_attempts_per_call_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="Canceled",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="4"} 1
+        grpc_client_attempts_per_call_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="Canceled",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="5"} 1
+        grpc_client_attempts_per_call_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="Canceled",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="10"} 1
+        grpc_client_attempts_per_call_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="Canceled",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="+Inf"} 1
+        grpc_client_attempts_per_call_histogram_sum{grpc_client_user_agent="fake-user-agent",grpc_code="Canceled",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service"} 1
+        grpc_client_attempts_per_call_histogram_count{grpc_client_user_agent="fake-user-agent",grpc_code="Canceled",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service"} 1
+		grpc_client_attempts_per_call_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="Unavailable",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="1"} 1
+        grpc_client_attempts_per_call_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="Unavailable",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="2"} 1
+        grpc_client_attempts_per_call_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="Unavailable",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="3"} 1
+        grpc_client_attempts_per_call_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="Unavailable",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="4"} 1
+        grpc_client_attempts_per_call_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="Unavailable",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="5"} 1
+        grpc_client_attempts_per_call_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="Unavailable",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="10"} 1
+        grpc_client_attempts_per_call_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="Unavailable",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="+Inf"} 1
+        grpc_client_attempts_per_call_histogram_sum{grpc_client_user_agent="fake-user-agent",grpc_code="Unavailable",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service"} 1
+        grpc_client_attempts_per_call_histogram_count{grpc_client_user_agent="fake-user-agent",grpc_code="Unavailable",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service"} 1
+	`
+
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_client_attempts_per_call_histogram"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,56 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewClientCallDurationHistogramVec allocates a new Prometheus HistogramVec for the client and given set of options.
+// It follows naming conventions of gRPC proposal A66.
+func NewClientCallDurationHistogramVec(opts ...CollectorOption) *prometheus.HistogramVec {
+	labels := []string{
+		labelMethod,
+		labelStatus,
+		labelTarget,
+	}
+	return newLatencyHistogramVec("client", "call_duration_seconds", "Time taken by gRPC to complete an RPC from application's perspective.", labels, opts...)
+}
+
+// ClientCallDurationStatsHandler is responsible for observing how long outgoing calls take from the application perspective, including all attempts,
+// following gRPC proposal A66 (grpc.client.call.duration).
+// It requires StatsHandler.UnaryClientInterceptor and StatsHandler.StreamClientInterceptor to be installed.
+type ClientCallDurationStatsHandler struct {
+	baseStatsHandler
+	vec prometheus.ObserverVec
+}
+
+// NewClientCallDurationStatsHandler ...
+func NewClientCallDurationStatsHandler(vec prometheus.ObserverVec, opts ...StatsHandlerOption) *ClientCallDurationStatsHandler {
+	h := &ClientCallDurationStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+			options: statsHandlerOptions{
+				handleRPCLabelFn: clientCallDurationLabels,
+			},
+		},
+		vec: vec,
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+func (h *ClientCallDurationStatsHandler) handleCall(ctx context.Context, _ int64, stat *stats.End) {
+	h.observe(ctx, h.vec, h.labelValues(ctx, stat), stat.EndTime.Sub(stat.BeginTime).Seconds())
+}
+
+func clientCallDurationLabels(ctx context.Context, stat stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	return []string{
+		tag.fullMethod,
+		statusCodeName(stat.(*stats.End).Error),
+		tag.target,
+	}
+}
@@ -0,0 +1,58 @@
+package promgrpc_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/piotrkowalczuk/promgrpc/v4/internal/testutil"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+)
+
+func TestNewClientCallDurationStatsHandler(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.NewStatsHandler(promgrpc.NewClientCallDurationStatsHandler(promgrpc.NewClientCallDurationHistogramVec()))
+	invoker := func(ctx context.Context, method string, _, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+		var err error
+		for i := 0; i < 2; i++ {
+			ctx := h.TagRPC(ctx, &stats.RPCTagInfo{
+				FullMethodName: method,
+			})
+			h.HandleRPC(ctx, &stats.Begin{Client: true})
+			time.Sleep(10 * time.Millisecond)
+			err = status.Error(codes.Unavailable, "unavailable")
+			h.HandleRPC(ctx, &stats.End{Client: true, Error: err})
+		}
+		return err
+	}
+
+	err := h.UnaryClientInterceptor()(ctx, "/package.Service/Method", nil, nil, nil, invoker)
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reg := prometheus.NewRegistry()
+	registerCollector(t, reg, h)
+
+	testutil.AssertMetricValue(t, reg, "grpc_client_call_duration_seconds_count", 1)
+	testutil.AssertMetricDimensions(t, reg, "grpc_client_call_duration_seconds_count", map[string]string{
+		"grpc_method": "package.Service/Method",
+		"grpc_status": "UNAVAILABLE",
+		"grpc_target": "n/a",
+	})
+
+	mf, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := mf[0].GetMetric()[0].GetHistogram().GetSampleSum(); got < 0.02 {
+		t.Errorf("call duration is too short, expected at least 0.02 but got %g", got)
+	}
+}
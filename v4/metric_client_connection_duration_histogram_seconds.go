@@ -0,0 +1,71 @@
+package promgrpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewClientConnectionDurationHistogramVec allocates a new Prometheus HistogramVec for the client and given set of options.
+func NewClientConnectionDurationHistogramVec(opts ...CollectorOption) *prometheus.HistogramVec {
+	labels := []string{labelLocalAddr}
+	return newConnectionDurationHistogramVec("client", labels, opts...)
+}
+
+// ClientConnectionDurationStatsHandler is responsible for observing how long outgoing connections stay open, from stats.ConnBegin until stats.ConnEnd.
+// Short-lived connections usually point at a load balancer idle timeout or at a peer that opens a connection per call.
+type ClientConnectionDurationStatsHandler struct {
+	baseStatsHandler
+	vec prometheus.ObserverVec
+}
+
+// NewClientConnectionDurationStatsHandler ...
+func NewClientConnectionDurationStatsHandler(vec prometheus.ObserverVec) *ClientConnectionDurationStatsHandler {
+	return &ClientConnectionDurationStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+			options: statsHandlerOptions{
+				client: true,
+			},
+		},
+		vec: vec,
+	}
+}
+
+// TagConn implements stats Handler interface.
+func (h *ClientConnectionDurationStatsHandler) TagConn(ctx context.Context, inf *stats.ConnTagInfo) context.Context {
+	ctx = h.baseStatsHandler.TagConn(ctx, inf)
+	ctx = context.WithValue(ctx, clientConnectionDurationKey{}, &connectionMark{})
+	return ctx
+}
+
+// HandleConn implements stats Handler interface.
+func (h *ClientConnectionDurationStatsHandler) HandleConn(ctx context.Context, stat stats.ConnStats) {
+	if !stat.IsClient() {
+		return
+	}
+	mrk, ok := ctx.Value(clientConnectionDurationKey{}).(*connectionMark)
+	if !ok {
+		return
+	}
+
+	switch stat.(type) {
+	case *stats.ConnBegin:
+		mrk.begin(time.Now())
+	case *stats.ConnEnd:
+		if elapsed, ok := mrk.end(time.Now()); ok {
+			h.vec.WithLabelValues(h.labels(ctx)...).Observe(elapsed.Seconds())
+		}
+	}
+}
+
+func (h *ClientConnectionDurationStatsHandler) labels(ctx context.Context) []string {
+	tag := ctx.Value(tagConnKey).(connTagLabels)
+	return []string{
+		tag.localAddr,
+	}
+}
+
+type clientConnectionDurationKey struct{}
@@ -0,0 +1,50 @@
+package promgrpc_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/piotrkowalczuk/promgrpc/v4/internal/testutil"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+func TestNewClientConnectionDurationStatsHandler(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.NewClientConnectionDurationStatsHandler(promgrpc.NewClientConnectionDurationHistogramVec())
+	ctx = h.TagConn(ctx, &stats.ConnTagInfo{
+		LocalAddr: &net.TCPAddr{
+			IP:   net.IPv4(1, 2, 3, 4),
+			Port: 4213412,
+		},
+		RemoteAddr: &net.TCPAddr{
+			IP:   net.IPv4(4, 3, 2, 1),
+			Port: 8080,
+		},
+	})
+	h.HandleConn(ctx, &stats.ConnBegin{Client: true})
+	time.Sleep(10 * time.Millisecond)
+	h.HandleConn(ctx, &stats.ConnEnd{})
+	h.HandleConn(ctx, &stats.ConnEnd{Client: true})
+
+	reg := prometheus.NewRegistry()
+	registerCollector(t, reg, h)
+
+	testutil.AssertMetricValue(t, reg, "grpc_client_connection_duration_histogram_seconds_count", 1)
+	testutil.AssertMetricDimensions(t, reg, "grpc_client_connection_duration_histogram_seconds_count", map[string]string{
+		"grpc_local_addr": "1.2.3.4",
+	})
+
+	mf, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := mf[0].GetMetric()[0].GetHistogram().GetSampleSum(); got < 0.01 {
+		t.Errorf("connection duration is too short, expected at least 0.01 but got %g", got)
+	}
+}
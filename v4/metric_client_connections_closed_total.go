@@ -0,0 +1,48 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewClientConnectionsClosedTotalCounterVec allocates a new Prometheus CounterVec for the client and given set of options.
+func NewClientConnectionsClosedTotalCounterVec(opts ...CollectorOption) *prometheus.CounterVec {
+	labels := []string{labelLocalAddr}
+	return newConnectionsClosedTotalCounterVec("client", labels, opts...)
+}
+
+// ClientConnectionsClosedTotalStatsHandler is responsible for counting outgoing connections once they are closed.
+// Unlike ClientConnectionsStatsHandler, it makes connection churn visible, e.g. connections dropped by a load balancer idle timeout.
+type ClientConnectionsClosedTotalStatsHandler struct {
+	baseStatsHandler
+	vec *prometheus.CounterVec
+}
+
+// NewClientConnectionsClosedTotalStatsHandler ...
+func NewClientConnectionsClosedTotalStatsHandler(vec *prometheus.CounterVec) *ClientConnectionsClosedTotalStatsHandler {
+	return &ClientConnectionsClosedTotalStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+			options: statsHandlerOptions{
+				client: true,
+			},
+		},
+		vec: vec,
+	}
+}
+
+// HandleConn implements stats Handler interface.
+func (h *ClientConnectionsClosedTotalStatsHandler) HandleConn(ctx context.Context, stat stats.ConnStats) {
+	if _, ok := stat.(*stats.ConnEnd); ok && stat.IsClient() {
+		h.vec.WithLabelValues(h.labels(ctx)...).Inc()
+	}
+}
+
+func (h *ClientConnectionsClosedTotalStatsHandler) labels(ctx context.Context) []string {
+	tag := ctx.Value(tagConnKey).(connTagLabels)
+	return []string{
+		tag.localAddr,
+	}
+}
@@ -0,0 +1,48 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewClientConnectionsOpenedTotalCounterVec allocates a new Prometheus CounterVec for the client and given set of options.
+func NewClientConnectionsOpenedTotalCounterVec(opts ...CollectorOption) *prometheus.CounterVec {
+	labels := []string{labelLocalAddr}
+	return newConnectionsOpenedTotalCounterVec("client", labels, opts...)
+}
+
+// ClientConnectionsOpenedTotalStatsHandler is responsible for counting outgoing connections once they are established.
+// Unlike ClientConnectionsStatsHandler, it makes connection churn visible, e.g. reconnects after a deployment.
+type ClientConnectionsOpenedTotalStatsHandler struct {
+	baseStatsHandler
+	vec *prometheus.CounterVec
+}
+
+// NewClientConnectionsOpenedTotalStatsHandler ...
+func NewClientConnectionsOpenedTotalStatsHandler(vec *prometheus.CounterVec) *ClientConnectionsOpenedTotalStatsHandler {
+	return &ClientConnectionsOpenedTotalStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+			options: statsHandlerOptions{
+				client: true,
+			},
+		},
+		vec: vec,
+	}
+}
+
+// HandleConn implements stats Handler interface.
+func (h *ClientConnectionsOpenedTotalStatsHandler) HandleConn(ctx context.Context, stat stats.ConnStats) {
+	if _, ok := stat.(*stats.ConnBegin); ok && stat.IsClient() {
+		h.vec.WithLabelValues(h.labels(ctx)...).Inc()
+	}
+}
+
+func (h *ClientConnectionsOpenedTotalStatsHandler) labels(ctx context.Context) []string {
+	tag := ctx.Value(tagConnKey).(connTagLabels)
+	return []string{
+		tag.localAddr,
+	}
+}
@@ -0,0 +1,54 @@
+package promgrpc_test
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/stats"
+)
+
+func TestNewClientConnectionsOpenedTotalStatsHandler(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.NewClientConnectionsOpenedTotalStatsHandler(promgrpc.NewClientConnectionsOpenedTotalCounterVec())
+	ctx1 := h.TagConn(ctx, &stats.ConnTagInfo{
+		LocalAddr: &net.TCPAddr{
+			IP:   net.IPv4(1, 2, 3, 4),
+			Port: 4213412,
+		},
+		RemoteAddr: &net.TCPAddr{
+			IP:   net.IPv4(4, 3, 2, 1),
+			Port: 8080,
+		},
+	})
+	h.HandleConn(ctx1, &stats.ConnBegin{Client: true})
+	h.HandleConn(ctx1, &stats.ConnBegin{})
+	ctx2 := h.TagConn(ctx, &stats.ConnTagInfo{
+		LocalAddr: &net.TCPAddr{
+			IP:   net.IPv4(1, 2, 3, 4),
+			Port: 543543,
+		},
+		RemoteAddr: &net.TCPAddr{
+			IP:   net.IPv4(4, 3, 2, 1),
+			Port: 8080,
+		},
+	})
+	h.HandleConn(ctx2, &stats.ConnBegin{Client: true})
+
+	const metadata = `
+		# HELP grpc_client_connections_opened_total Number of connections opened.
+		# TYPE grpc_client_connections_opened_total counter
+	`
+	expected := `
+		grpc_client_connections_opened_total{grpc_local_addr="1.2.3.4"} 2
+	`
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_client_connections_opened_total"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,57 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewClientDelayedPicksTotalCounterVec allocates a new Prometheus CounterVec for the client and given set of options.
+func NewClientDelayedPicksTotalCounterVec(opts ...CollectorOption) *prometheus.CounterVec {
+	labels := []string{
+		labelIsFailFast,
+		labelMethod,
+		labelService,
+	}
+	return newDelayedPicksTotalCounterVec("client", labels, opts...)
+}
+
+// ClientDelayedPicksTotalStatsHandler is responsible for counting RPCs held by a load balancer or a name resolver.
+// Every time an RPC, that is waiting for a pick, receives a new picker, gRPC reports stats.PickerUpdated (formerly stats.DelayedPickComplete).
+type ClientDelayedPicksTotalStatsHandler struct {
+	baseStatsHandler
+	vec *prometheus.CounterVec
+}
+
+// NewClientDelayedPicksTotalStatsHandler ...
+func NewClientDelayedPicksTotalStatsHandler(vec *prometheus.CounterVec, opts ...StatsHandlerOption) *ClientDelayedPicksTotalStatsHandler {
+	h := &ClientDelayedPicksTotalStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+			options: statsHandlerOptions{
+				handleRPCLabelFn: clientDelayedPicksTotalLabels,
+			},
+		},
+		vec: vec,
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ClientDelayedPicksTotalStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if _, ok := stat.(*stats.PickerUpdated); ok {
+		h.inc(ctx, h.vec.WithLabelValues(h.labelValues(ctx, stat)...))
+	}
+}
+
+func clientDelayedPicksTotalLabels(ctx context.Context, _ stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	return []string{
+		tag.isFailFast,
+		tag.method,
+		tag.service,
+	}
+}
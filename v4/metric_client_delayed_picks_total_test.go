@@ -0,0 +1,43 @@
+package promgrpc_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/stats"
+)
+
+func TestNewClientDelayedPicksTotalStatsHandler(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.NewStatsHandler(promgrpc.NewClientDelayedPicksTotalStatsHandler(promgrpc.NewClientDelayedPicksTotalCounterVec()))
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+		FullMethodName: "/service/Method",
+		FailFast:       false,
+	})
+	h.HandleRPC(ctx, &stats.Begin{
+		Client: true,
+	})
+	h.HandleRPC(ctx, &stats.PickerUpdated{})
+	h.HandleRPC(ctx, &stats.PickerUpdated{})
+	h.HandleRPC(ctx, &stats.OutHeader{
+		Client: true,
+	})
+
+	const metadata = `
+		# HELP grpc_client_delayed_picks_total Number of times an RPC blocked by a load balancer received a new picker.
+		# TYPE grpc_client_delayed_picks_total counter
+	`
+	expected := `
+		grpc_client_delayed_picks_total{grpc_is_fail_fast="false",grpc_method="Method",grpc_service="service"} 2
+	`
+
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_client_delayed_picks_total"); err != nil {
+		t.Fatal(err)
+	}
+}
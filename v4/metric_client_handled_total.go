@@ -0,0 +1,67 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+)
+
+// NewClientHandledCounterVec allocates a new Prometheus CounterVec for the client and given set of options.
+// It is compatible with go-grpc-prometheus.
+func NewClientHandledCounterVec(opts ...CollectorOption) *prometheus.CounterVec {
+	labels := []string{
+		// keep alphabetical order
+		labelCode,
+		labelMethod,
+		labelService,
+		labelType,
+	}
+	return newHandledTotalCounterVec("client", "Total number of RPCs completed by the client, regardless of success or failure.", labels, opts...)
+}
+
+// ClientHandledStatsHandler is responsible for counting outgoing RPCs once they complete, regardless of success or failure, the same way go-grpc-prometheus does (grpc_client_handled_total).
+// If a call is intercepted (see StatsHandler.UnaryClientInterceptor), it is counted once it completes, with the status of the call.
+type ClientHandledStatsHandler struct {
+	baseStatsHandler
+	vec *prometheus.CounterVec
+}
+
+// NewClientHandledStatsHandler ...
+func NewClientHandledStatsHandler(vec *prometheus.CounterVec, opts ...StatsHandlerOption) *ClientHandledStatsHandler {
+	h := &ClientHandledStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+			options: statsHandlerOptions{
+				handleRPCLabelFn: clientHandledLabels,
+			},
+		},
+		vec: vec,
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ClientHandledStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if _, ok := stat.(*stats.End); ok && stat.IsClient() && !ctx.Value(tagRPCKey).(*rpcTagLabels).intercepted() {
+		h.inc(ctx, h.vec.WithLabelValues(h.labelValues(ctx, stat)...))
+	}
+}
+
+func (h *ClientHandledStatsHandler) handleCall(ctx context.Context, _ int64, stat *stats.End) {
+	h.inc(ctx, h.vec.WithLabelValues(h.labelValues(ctx, stat)...))
+}
+
+func clientHandledLabels(ctx context.Context, stat stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	// keep alphabetical order
+	return []string{
+		status.Code(stat.(*stats.End).Error).String(),
+		tag.method,
+		tag.service,
+		tag.rpcType,
+	}
+}
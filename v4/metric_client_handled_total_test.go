@@ -0,0 +1,96 @@
+package promgrpc_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+)
+
+func TestNewClientHandledStatsHandler(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.NewStatsHandler(promgrpc.NewClientHandledStatsHandler(promgrpc.NewClientHandledCounterVec()))
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+		FullMethodName: "/service/Method",
+		FailFast:       true,
+	})
+	begin := time.Now()
+	h.HandleRPC(ctx, &stats.Begin{
+		Client:         true,
+		BeginTime:      begin,
+		IsClientStream: true,
+		IsServerStream: true,
+	})
+	h.HandleRPC(ctx, &stats.End{
+		Client:    true,
+		BeginTime: begin,
+		EndTime:   begin.Add(100 * time.Millisecond),
+		Error:     status.Error(codes.Unavailable, "unavailable"),
+	})
+	h.HandleRPC(ctx, &stats.End{
+		BeginTime: begin,
+		EndTime:   begin.Add(100 * time.Millisecond),
+	})
+
+	const metadata = `
+		# HELP grpc_client_handled_total Total number of RPCs completed by the client, regardless of success or failure.
+        # TYPE grpc_client_handled_total counter
+	`
+	expected := `
+		grpc_client_handled_total{grpc_code="Unavailable",grpc_method="Method",grpc_service="service",grpc_type="bidi_stream"} 1
+	`
+
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_client_handled_total"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewClientHandledStatsHandler_intercepted(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.GoGRPCPrometheusClientStatsHandler()
+	interceptor := h.UnaryClientInterceptor()
+
+	// A call that succeeds at the third attempt.
+	invoker := func(ctx context.Context, method string, _, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+		var err error
+		for _, err = range []error{status.Error(codes.Unavailable, "unavailable"), status.Error(codes.Unavailable, "unavailable"), nil} {
+			ctx := h.TagRPC(ctx, &stats.RPCTagInfo{FullMethodName: method})
+			begin := time.Now()
+			h.HandleRPC(ctx, &stats.Begin{Client: true, BeginTime: begin})
+			h.HandleRPC(ctx, &stats.End{Client: true, BeginTime: begin, EndTime: begin.Add(100 * time.Millisecond), Error: err})
+		}
+		return err
+	}
+	if err := interceptor(ctx, "/service/Method", nil, nil, nil, invoker); err != nil {
+		t.Fatal(err)
+	}
+
+	const metadata = `
+		# HELP grpc_client_handled_total Total number of RPCs completed by the client, regardless of success or failure.
+        # TYPE grpc_client_handled_total counter
+		# HELP grpc_client_started_total Total number of RPCs started on the client.
+        # TYPE grpc_client_started_total counter
+	`
+	expected := `
+		grpc_client_handled_total{grpc_code="OK",grpc_method="Method",grpc_service="service",grpc_type="unary"} 1
+		grpc_client_started_total{grpc_method="Method",grpc_service="service",grpc_type="unary"} 1
+	`
+
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_client_handled_total", "grpc_client_started_total"); err != nil {
+		t.Fatal(err)
+	}
+	if n := testutil.CollectAndCount(h, "grpc_client_handling_seconds"); n != 1 {
+		t.Fatalf("unexpected number of series, expected 1 but got %d", n)
+	}
+}
@@ -0,0 +1,67 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+)
+
+// NewClientHandlingSecondsHistogramVec allocates a new Prometheus HistogramVec for the client and given set of options.
+// It is compatible with go-grpc-prometheus.
+func NewClientHandlingSecondsHistogramVec(opts ...CollectorOption) *prometheus.HistogramVec {
+	labels := []string{
+		// keep alphabetical order
+		labelCode,
+		labelMethod,
+		labelService,
+		labelType,
+	}
+	return newHandlingSecondsHistogramVec("client", "Histogram of response latency (seconds) of the gRPC until it is finished by the application.", labels, opts...)
+}
+
+// ClientHandlingSecondsStatsHandler is responsible for observing how long outgoing RPCs take, the same way go-grpc-prometheus does (grpc_client_handling_seconds).
+// If a call is intercepted (see StatsHandler.UnaryClientInterceptor), it is observed once it completes, including all its attempts.
+type ClientHandlingSecondsStatsHandler struct {
+	baseStatsHandler
+	vec prometheus.ObserverVec
+}
+
+// NewClientHandlingSecondsStatsHandler ...
+func NewClientHandlingSecondsStatsHandler(vec prometheus.ObserverVec, opts ...StatsHandlerOption) *ClientHandlingSecondsStatsHandler {
+	h := &ClientHandlingSecondsStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+			options: statsHandlerOptions{
+				handleRPCLabelFn: clientHandlingSecondsLabels,
+			},
+		},
+		vec: vec,
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ClientHandlingSecondsStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if pay, ok := stat.(*stats.End); ok && stat.IsClient() && !ctx.Value(tagRPCKey).(*rpcTagLabels).intercepted() {
+		h.observe(ctx, h.vec, h.labelValues(ctx, stat), pay.EndTime.Sub(pay.BeginTime).Seconds())
+	}
+}
+
+func (h *ClientHandlingSecondsStatsHandler) handleCall(ctx context.Context, _ int64, stat *stats.End) {
+	h.observe(ctx, h.vec, h.labelValues(ctx, stat), stat.EndTime.Sub(stat.BeginTime).Seconds())
+}
+
+func clientHandlingSecondsLabels(ctx context.Context, stat stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	// keep alphabetical order
+	return []string{
+		status.Code(stat.(*stats.End).Error).String(),
+		tag.method,
+		tag.service,
+		tag.rpcType,
+	}
+}
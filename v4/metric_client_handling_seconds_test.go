@@ -0,0 +1,67 @@
+package promgrpc_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+)
+
+func TestNewClientHandlingSecondsStatsHandler(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.NewStatsHandler(promgrpc.NewClientHandlingSecondsStatsHandler(promgrpc.NewClientHandlingSecondsHistogramVec()))
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+		FullMethodName: "/service/Method",
+		FailFast:       true,
+	})
+	begin := time.Now()
+	h.HandleRPC(ctx, &stats.Begin{
+		Client:         true,
+		BeginTime:      begin,
+		IsClientStream: true,
+		IsServerStream: true,
+	})
+	h.HandleRPC(ctx, &stats.End{
+		Client:    true,
+		BeginTime: begin,
+		EndTime:   begin.Add(100 * time.Millisecond),
+		Error:     status.Error(codes.Unavailable, "unavailable"),
+	})
+	h.HandleRPC(ctx, &stats.End{
+		BeginTime: begin,
+		EndTime:   begin.Add(100 * time.Millisecond),
+	})
+
+	const metadata = `
+		# HELP grpc_client_handling_seconds Histogram of response latency (seconds) of the gRPC until it is finished by the application.
+        # TYPE grpc_client_handling_seconds histogram
+	`
+	expected := `
+		grpc_client_handling_seconds_bucket{grpc_code="Unavailable",grpc_method="Method",grpc_service="service",grpc_type="bidi_stream",le="0.005"} 0
+		grpc_client_handling_seconds_bucket{grpc_code="Unavailable",grpc_method="Method",grpc_service="service",grpc_type="bidi_stream",le="0.01"} 0
+		grpc_client_handling_seconds_bucket{grpc_code="Unavailable",grpc_method="Method",grpc_service="service",grpc_type="bidi_stream",le="0.025"} 0
+		grpc_client_handling_seconds_bucket{grpc_code="Unavailable",grpc_method="Method",grpc_service="service",grpc_type="bidi_stream",le="0.05"} 0
+		grpc_client_handling_seconds_bucket{grpc_code="Unavailable",grpc_method="Method",grpc_service="service",grpc_type="bidi_stream",le="0.1"} 1
+		grpc_client_handling_seconds_bucket{grpc_code="Unavailable",grpc_method="Method",grpc_service="service",grpc_type="bidi_stream",le="0.25"} 1
+		grpc_client_handling_seconds_bucket{grpc_code="Unavailable",grpc_method="Method",grpc_service="service",grpc_type="bidi_stream",le="0.5"} 1
+		grpc_client_handling_seconds_bucket{grpc_code="Unavailable",grpc_method="Method",grpc_service="service",grpc_type="bidi_stream",le="1"} 1
+		grpc_client_handling_seconds_bucket{grpc_code="Unavailable",grpc_method="Method",grpc_service="service",grpc_type="bidi_stream",le="2.5"} 1
+		grpc_client_handling_seconds_bucket{grpc_code="Unavailable",grpc_method="Method",grpc_service="service",grpc_type="bidi_stream",le="5"} 1
+		grpc_client_handling_seconds_bucket{grpc_code="Unavailable",grpc_method="Method",grpc_service="service",grpc_type="bidi_stream",le="10"} 1
+		grpc_client_handling_seconds_bucket{grpc_code="Unavailable",grpc_method="Method",grpc_service="service",grpc_type="bidi_stream",le="+Inf"} 1
+		grpc_client_handling_seconds_sum{grpc_code="Unavailable",grpc_method="Method",grpc_service="service",grpc_type="bidi_stream"} 0.1
+		grpc_client_handling_seconds_count{grpc_code="Unavailable",grpc_method="Method",grpc_service="service",grpc_type="bidi_stream"} 1
+	`
+
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_client_handling_seconds"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,69 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/piotrkowalczuk/promgrpc/v4/internal/useragent"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewClientMessageReceivedCompressionRatioHistogramVec allocates a new Prometheus HistogramVec for the client and given set of options.
+func NewClientMessageReceivedCompressionRatioHistogramVec(opts ...CollectorOption) *prometheus.HistogramVec {
+	labels := []string{
+		labelIsFailFast,
+		labelMethod,
+		labelService,
+		labelClientUserAgent,
+	}
+	return newMessageReceivedCompressionRatioHistogramVec("client", labels, opts...)
+}
+
+// ClientMessageReceivedCompressionRatioStatsHandler is responsible for observing the ratio between uncompressed and compressed size of messages received.
+// A ratio lower than one means that compression increased the size of a message.
+// Empty messages are not observed.
+type ClientMessageReceivedCompressionRatioStatsHandler struct {
+	baseStatsHandler
+	uas useragent.Store
+	vec prometheus.ObserverVec
+}
+
+// NewClientMessageReceivedCompressionRatioStatsHandler ...
+func NewClientMessageReceivedCompressionRatioStatsHandler(vec prometheus.ObserverVec, opts ...StatsHandlerOption) *ClientMessageReceivedCompressionRatioStatsHandler {
+	h := &ClientMessageReceivedCompressionRatioStatsHandler{
+		vec: vec,
+	}
+	h.baseStatsHandler = baseStatsHandler{
+		collector:       vec,
+		supportedLabels: optionalLabelCompression,
+		options: statsHandlerOptions{
+			handleRPCLabelFn: h.labels,
+		},
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ClientMessageReceivedCompressionRatioStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	switch pay := stat.(type) {
+	case *stats.InPayload:
+		if stat.IsClient() && pay.CompressedLength > 0 {
+			h.observe(ctx, h.vec, h.labelValues(ctx, stat), float64(pay.Length)/float64(pay.CompressedLength))
+		}
+	case *stats.OutHeader:
+		_ = h.uas.ClientSide(ctx, pay)
+	}
+}
+
+func (h *ClientMessageReceivedCompressionRatioStatsHandler) labels(ctx context.Context, stat stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	return []string{
+		tag.isFailFast,
+		tag.method,
+		tag.service,
+		h.uas.ClientSide(ctx, stat),
+	}
+}
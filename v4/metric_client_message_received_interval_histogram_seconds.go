@@ -0,0 +1,80 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/piotrkowalczuk/promgrpc/v4/internal/useragent"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewClientMessageReceivedIntervalHistogramVec allocates a new Prometheus HistogramVec for the client and given set of options.
+func NewClientMessageReceivedIntervalHistogramVec(opts ...CollectorOption) *prometheus.HistogramVec {
+	labels := []string{
+		labelIsFailFast,
+		labelMethod,
+		labelService,
+		labelClientUserAgent,
+	}
+	return newMessageReceivedIntervalHistogramVec("client", labels, opts...)
+}
+
+// ClientMessageReceivedIntervalStatsHandler is responsible for measuring gaps between consecutive messages received within a single stream.
+// It makes stalled streams visible, even if they eventually succeed.
+type ClientMessageReceivedIntervalStatsHandler struct {
+	baseStatsHandler
+	uas useragent.Store
+	vec prometheus.ObserverVec
+}
+
+// NewClientMessageReceivedIntervalStatsHandler ...
+func NewClientMessageReceivedIntervalStatsHandler(vec prometheus.ObserverVec, opts ...StatsHandlerOption) *ClientMessageReceivedIntervalStatsHandler {
+	h := &ClientMessageReceivedIntervalStatsHandler{
+		vec: vec,
+	}
+	h.baseStatsHandler = baseStatsHandler{
+		collector: vec,
+		options: statsHandlerOptions{
+			handleRPCLabelFn: h.labels,
+		},
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// TagRPC implements stats Handler interface.
+func (h *ClientMessageReceivedIntervalStatsHandler) TagRPC(ctx context.Context, inf *stats.RPCTagInfo) context.Context {
+	ctx = h.baseStatsHandler.TagRPC(ctx, inf)
+	ctx = context.WithValue(ctx, clientMessageReceivedIntervalKey{}, &messageIntervalMark{})
+	return ctx
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ClientMessageReceivedIntervalStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	switch pay := stat.(type) {
+	case *stats.InPayload:
+		if stat.IsClient() {
+			if mrk, ok := ctx.Value(clientMessageReceivedIntervalKey{}).(*messageIntervalMark); ok {
+				if interval, ok := mrk.next(pay.RecvTime); ok {
+					h.observe(ctx, h.vec, h.labelValues(ctx, stat), interval.Seconds())
+				}
+			}
+		}
+	case *stats.OutHeader:
+		_ = h.uas.ClientSide(ctx, pay)
+	}
+}
+
+func (h *ClientMessageReceivedIntervalStatsHandler) labels(ctx context.Context, stat stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	return []string{
+		tag.isFailFast,
+		tag.method,
+		tag.service,
+		h.uas.ClientSide(ctx, stat),
+	}
+}
+
+type clientMessageReceivedIntervalKey struct{}
@@ -0,0 +1,68 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/piotrkowalczuk/promgrpc/v4/internal/useragent"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewClientMessageReceivedWireSizeHistogramVec allocates a new Prometheus HistogramVec for the client and given set of options.
+func NewClientMessageReceivedWireSizeHistogramVec(opts ...CollectorOption) *prometheus.HistogramVec {
+	labels := []string{
+		labelIsFailFast,
+		labelMethod,
+		labelService,
+		labelClientUserAgent,
+	}
+	return newMessageReceivedWireSizeHistogramVec("client", labels, opts...)
+}
+
+// ClientMessageReceivedWireSizeStatsHandler is responsible for observing the size of messages received as they are sent over the wire, after compression and including gRPC framing.
+// Compared against ClientMessageReceivedSizeStatsHandler, it shows how much compression actually saves.
+type ClientMessageReceivedWireSizeStatsHandler struct {
+	baseStatsHandler
+	uas useragent.Store
+	vec prometheus.ObserverVec
+}
+
+// NewClientMessageReceivedWireSizeStatsHandler ...
+func NewClientMessageReceivedWireSizeStatsHandler(vec prometheus.ObserverVec, opts ...StatsHandlerOption) *ClientMessageReceivedWireSizeStatsHandler {
+	h := &ClientMessageReceivedWireSizeStatsHandler{
+		vec: vec,
+	}
+	h.baseStatsHandler = baseStatsHandler{
+		collector:       vec,
+		supportedLabels: optionalLabelCompression | optionalLabelMessageType,
+		options: statsHandlerOptions{
+			handleRPCLabelFn: h.labels,
+		},
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ClientMessageReceivedWireSizeStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	switch pay := stat.(type) {
+	case *stats.InPayload:
+		if stat.IsClient() {
+			h.observe(ctx, h.vec, h.labelValues(ctx, stat), float64(pay.WireLength))
+		}
+	case *stats.OutHeader:
+		_ = h.uas.ClientSide(ctx, pay)
+	}
+}
+
+func (h *ClientMessageReceivedWireSizeStatsHandler) labels(ctx context.Context, stat stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	return []string{
+		tag.isFailFast,
+		tag.method,
+		tag.service,
+		h.uas.ClientSide(ctx, stat),
+	}
+}
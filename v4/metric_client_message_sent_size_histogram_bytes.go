@@ -31,7 +31,8 @@ func NewClientMessageSentSizeStatsHandler(vec prometheus.ObserverVec, opts ...St
 		vec: vec,
 	}
 	h.baseStatsHandler = baseStatsHandler{
-		collector: vec,
+		collector:       vec,
+		supportedLabels: optionalLabelCompression | optionalLabelMessageType,
 		options: statsHandlerOptions{
 			handleRPCLabelFn: h.labels,
 		},
@@ -46,7 +47,7 @@ func (h *ClientMessageSentSizeStatsHandler) HandleRPC(ctx context.Context, stat
 	switch pay := stat.(type) {
 	case *stats.OutPayload:
 		if stat.IsClient() {
-			h.vec.WithLabelValues(h.options.handleRPCLabelFn(ctx, stat)...).Observe(float64(pay.Length))
+			h.observe(ctx, h.vec, h.labelValues(ctx, stat), float64(pay.Length))
 		}
 	case *stats.OutHeader:
 		_ = h.uas.ClientSide(ctx, pay)
@@ -54,7 +55,7 @@ func (h *ClientMessageSentSizeStatsHandler) HandleRPC(ctx context.Context, stat
 }
 
 func (h *ClientMessageSentSizeStatsHandler) labels(ctx context.Context, stat stats.RPCStats) []string {
-	tag := ctx.Value(tagRPCKey).(rpcTagLabels)
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
 	return []string{
 		tag.isFailFast,
 		tag.method,
@@ -0,0 +1,88 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/piotrkowalczuk/promgrpc/v4/internal/useragent"
+
+	"google.golang.org/grpc/status"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewClientMessagesReceivedPerRequestHistogramVec allocates a new Prometheus HistogramVec for the client and given set of options.
+func NewClientMessagesReceivedPerRequestHistogramVec(opts ...CollectorOption) *prometheus.HistogramVec {
+	labels := []string{
+		labelCode,
+		labelIsFailFast,
+		labelMethod,
+		labelService,
+		labelClientUserAgent,
+	}
+	return newMessagesReceivedPerRequestHistogramVec("client", labels, opts...)
+}
+
+// ClientMessagesReceivedPerRequestStatsHandler is responsible for observing the number of messages received within a single RPC.
+// The total is observed once the RPC ends.
+type ClientMessagesReceivedPerRequestStatsHandler struct {
+	baseStatsHandler
+	uas useragent.Store
+	vec prometheus.ObserverVec
+}
+
+// NewClientMessagesReceivedPerRequestStatsHandler ...
+func NewClientMessagesReceivedPerRequestStatsHandler(vec prometheus.ObserverVec, opts ...StatsHandlerOption) *ClientMessagesReceivedPerRequestStatsHandler {
+	h := &ClientMessagesReceivedPerRequestStatsHandler{
+		vec: vec,
+	}
+	h.baseStatsHandler = baseStatsHandler{
+		collector: vec,
+		options: statsHandlerOptions{
+			handleRPCLabelFn: h.labels,
+		},
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// TagRPC implements stats Handler interface.
+func (h *ClientMessagesReceivedPerRequestStatsHandler) TagRPC(ctx context.Context, inf *stats.RPCTagInfo) context.Context {
+	ctx = h.baseStatsHandler.TagRPC(ctx, inf)
+	ctx = context.WithValue(ctx, clientMessagesReceivedPerRequestKey{}, &requestTotalMark{})
+	return ctx
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ClientMessagesReceivedPerRequestStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if !stat.IsClient() {
+		return
+	}
+
+	switch pay := stat.(type) {
+	case *stats.InPayload:
+		if mrk, ok := ctx.Value(clientMessagesReceivedPerRequestKey{}).(*requestTotalMark); ok {
+			mrk.add(1)
+		}
+	case *stats.End:
+		if mrk, ok := ctx.Value(clientMessagesReceivedPerRequestKey{}).(*requestTotalMark); ok {
+			h.observe(ctx, h.vec, h.labelValues(ctx, stat), float64(mrk.load()))
+		}
+	case *stats.OutHeader:
+		_ = h.uas.ClientSide(ctx, pay)
+	}
+}
+
+func (h *ClientMessagesReceivedPerRequestStatsHandler) labels(ctx context.Context, stat stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	return []string{
+		status.Code(stat.(*stats.End).Error).String(),
+		tag.isFailFast,
+		tag.method,
+		tag.service,
+		h.uas.ClientSide(ctx, stat),
+	}
+}
+
+type clientMessagesReceivedPerRequestKey struct{}
@@ -33,7 +33,8 @@ func NewClientMessagesReceivedTotalStatsHandler(vec *prometheus.CounterVec, opts
 		vec: vec,
 	}
 	h.baseStatsHandler = baseStatsHandler{
-		collector: vec,
+		collector:       vec,
+		supportedLabels: optionalLabelMessageType,
 		options: statsHandlerOptions{
 			handleRPCLabelFn: h.labels,
 		},
@@ -48,7 +49,7 @@ func (h *ClientMessagesReceivedTotalStatsHandler) HandleRPC(ctx context.Context,
 	switch pay := stat.(type) {
 	case *stats.InPayload:
 		if stat.IsClient() {
-			h.vec.WithLabelValues(h.options.handleRPCLabelFn(ctx, stat)...).Inc()
+			h.inc(ctx, h.vec.WithLabelValues(h.labelValues(ctx, stat)...))
 		}
 	case *stats.OutHeader:
 		_ = h.uas.ClientSide(ctx, pay)
@@ -56,7 +57,7 @@ func (h *ClientMessagesReceivedTotalStatsHandler) HandleRPC(ctx context.Context,
 }
 
 func (h *ClientMessagesReceivedTotalStatsHandler) labels(ctx context.Context, stat stats.RPCStats) []string {
-	tag := ctx.Value(tagRPCKey).(rpcTagLabels)
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
 	return []string{
 		tag.isFailFast,
 		tag.method,
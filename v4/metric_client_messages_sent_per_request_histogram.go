@@ -0,0 +1,88 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/piotrkowalczuk/promgrpc/v4/internal/useragent"
+
+	"google.golang.org/grpc/status"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewClientMessagesSentPerRequestHistogramVec allocates a new Prometheus HistogramVec for the client and given set of options.
+func NewClientMessagesSentPerRequestHistogramVec(opts ...CollectorOption) *prometheus.HistogramVec {
+	labels := []string{
+		labelCode,
+		labelIsFailFast,
+		labelMethod,
+		labelService,
+		labelClientUserAgent,
+	}
+	return newMessagesSentPerRequestHistogramVec("client", labels, opts...)
+}
+
+// ClientMessagesSentPerRequestStatsHandler is responsible for observing the number of messages sent within a single RPC.
+// The total is observed once the RPC ends.
+type ClientMessagesSentPerRequestStatsHandler struct {
+	baseStatsHandler
+	uas useragent.Store
+	vec prometheus.ObserverVec
+}
+
+// NewClientMessagesSentPerRequestStatsHandler ...
+func NewClientMessagesSentPerRequestStatsHandler(vec prometheus.ObserverVec, opts ...StatsHandlerOption) *ClientMessagesSentPerRequestStatsHandler {
+	h := &ClientMessagesSentPerRequestStatsHandler{
+		vec: vec,
+	}
+	h.baseStatsHandler = baseStatsHandler{
+		collector: vec,
+		options: statsHandlerOptions{
+			handleRPCLabelFn: h.labels,
+		},
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// TagRPC implements stats Handler interface.
+func (h *ClientMessagesSentPerRequestStatsHandler) TagRPC(ctx context.Context, inf *stats.RPCTagInfo) context.Context {
+	ctx = h.baseStatsHandler.TagRPC(ctx, inf)
+	ctx = context.WithValue(ctx, clientMessagesSentPerRequestKey{}, &requestTotalMark{})
+	return ctx
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ClientMessagesSentPerRequestStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if !stat.IsClient() {
+		return
+	}
+
+	switch pay := stat.(type) {
+	case *stats.OutPayload:
+		if mrk, ok := ctx.Value(clientMessagesSentPerRequestKey{}).(*requestTotalMark); ok {
+			mrk.add(1)
+		}
+	case *stats.End:
+		if mrk, ok := ctx.Value(clientMessagesSentPerRequestKey{}).(*requestTotalMark); ok {
+			h.observe(ctx, h.vec, h.labelValues(ctx, stat), float64(mrk.load()))
+		}
+	case *stats.OutHeader:
+		_ = h.uas.ClientSide(ctx, pay)
+	}
+}
+
+func (h *ClientMessagesSentPerRequestStatsHandler) labels(ctx context.Context, stat stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	return []string{
+		status.Code(stat.(*stats.End).Error).String(),
+		tag.isFailFast,
+		tag.method,
+		tag.service,
+		h.uas.ClientSide(ctx, stat),
+	}
+}
+
+type clientMessagesSentPerRequestKey struct{}
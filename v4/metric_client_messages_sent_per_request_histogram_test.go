@@ -0,0 +1,73 @@
+package promgrpc_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+)
+
+func TestNewClientMessagesSentPerRequestStatsHandler(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.NewStatsHandler(promgrpc.NewClientMessagesSentPerRequestStatsHandler(promgrpc.NewClientMessagesSentPerRequestHistogramVec()))
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+		FullMethodName: "/service/Method",
+		FailFast:       true,
+	})
+	h.HandleRPC(ctx, &stats.OutHeader{
+		Client: true,
+		Header: metadata.MD{"user-agent": []string{"fake-user-agent"}},
+	})
+	h.HandleRPC(ctx, &stats.OutPayload{
+		Client: true,
+		Length: 100,
+	})
+	h.HandleRPC(ctx, &stats.OutPayload{
+		Client: true,
+		Length: 200,
+	})
+	h.HandleRPC(ctx, &stats.OutPayload{
+		Client: true,
+		Length: 300,
+	})
+	h.HandleRPC(ctx, &stats.InPayload{
+		Client: true,
+		Length: 1000,
+	})
+	h.HandleRPC(ctx, &stats.End{
+		Client: true,
+	})
+	h.HandleRPC(ctx, &stats.End{})
+
+	const metadata = `
+		# HELP grpc_client_messages_sent_per_request_histogram Number of messages sent within a single RPC.
+        # TYPE grpc_client_messages_sent_per_request_histogram histogram
+	`
+	expected := `
+		grpc_client_messages_sent_per_request_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="1"} 0
+        grpc_client_messages_sent_per_request_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="2"} 0
+        grpc_client_messages_sent_per_request_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="4"} 1
+        grpc_client_messages_sent_per_request_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="8"} 1
+        grpc_client_messages_sent_per_request_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="16"} 1
+        grpc_client_messages_sent_per_request_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="32"} 1
+        grpc_client_messages_sent_per_request_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="64"} 1
+        grpc_client_messages_sent_per_request_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="128"} 1
+        grpc_client_messages_sent_per_request_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="256"} 1
+        grpc_client_messages_sent_per_request_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="512"} 1
+        grpc_client_messages_sent_per_request_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="1024"} 1
+        grpc_client_messages_sent_per_request_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="+Inf"} 1
+        grpc_client_messages_sent_per_request_histogram_sum{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service"} 3
+        grpc_client_messages_sent_per_request_histogram_count{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service"} 1
+	`
+
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_client_messages_sent_per_request_histogram"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,72 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/piotrkowalczuk/promgrpc/v4/internal/useragent"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewClientMetadataReceivedKeysHistogramVec allocates a new Prometheus HistogramVec for the client and given set of options.
+func NewClientMetadataReceivedKeysHistogramVec(opts ...CollectorOption) *prometheus.HistogramVec {
+	labels := []string{
+		labelIsFailFast,
+		labelMetadataKind,
+		labelMethod,
+		labelService,
+		labelClientUserAgent,
+	}
+	return newMetadataReceivedKeysHistogramVec("client", labels, opts...)
+}
+
+// ClientMetadataReceivedKeysStatsHandler is responsible for observing the number of metadata keys received within response headers and trailers.
+type ClientMetadataReceivedKeysStatsHandler struct {
+	baseStatsHandler
+	uas useragent.Store
+	vec prometheus.ObserverVec
+}
+
+// NewClientMetadataReceivedKeysStatsHandler ...
+func NewClientMetadataReceivedKeysStatsHandler(vec prometheus.ObserverVec, opts ...StatsHandlerOption) *ClientMetadataReceivedKeysStatsHandler {
+	h := &ClientMetadataReceivedKeysStatsHandler{
+		vec: vec,
+	}
+	h.baseStatsHandler = baseStatsHandler{
+		collector: vec,
+		options: statsHandlerOptions{
+			handleRPCLabelFn: h.labels,
+		},
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ClientMetadataReceivedKeysStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	switch pay := stat.(type) {
+	case *stats.InHeader:
+		if stat.IsClient() {
+			h.observe(ctx, h.vec, h.labelValues(ctx, stat), float64(len(pay.Header)))
+		}
+	case *stats.InTrailer:
+		if stat.IsClient() {
+			h.observe(ctx, h.vec, h.labelValues(ctx, stat), float64(len(pay.Trailer)))
+		}
+	case *stats.OutHeader:
+		_ = h.uas.ClientSide(ctx, pay)
+	}
+}
+
+func (h *ClientMetadataReceivedKeysStatsHandler) labels(ctx context.Context, stat stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	return []string{
+		tag.isFailFast,
+		metadataKind(stat),
+		tag.method,
+		tag.service,
+		h.uas.ClientSide(ctx, stat),
+	}
+}
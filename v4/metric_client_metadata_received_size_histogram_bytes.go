@@ -0,0 +1,74 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/piotrkowalczuk/promgrpc/v4/internal/useragent"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewClientMetadataReceivedSizeHistogramVec allocates a new Prometheus HistogramVec for the client and given set of options.
+func NewClientMetadataReceivedSizeHistogramVec(opts ...CollectorOption) *prometheus.HistogramVec {
+	labels := []string{
+		labelIsFailFast,
+		labelMetadataKind,
+		labelMethod,
+		labelService,
+		labelClientUserAgent,
+	}
+	return newMetadataReceivedSizeHistogramVec("client", labels, opts...)
+}
+
+// ClientMetadataReceivedSizeStatsHandler is responsible for observing the size of incoming response headers and trailers.
+// The size is computed the same way HTTP/2 computes a header list size, before HPACK compression,
+// so that it is comparable with the size of sent metadata, see ClientMetadataSentSizeStatsHandler.
+type ClientMetadataReceivedSizeStatsHandler struct {
+	baseStatsHandler
+	uas useragent.Store
+	vec prometheus.ObserverVec
+}
+
+// NewClientMetadataReceivedSizeStatsHandler ...
+func NewClientMetadataReceivedSizeStatsHandler(vec prometheus.ObserverVec, opts ...StatsHandlerOption) *ClientMetadataReceivedSizeStatsHandler {
+	h := &ClientMetadataReceivedSizeStatsHandler{
+		vec: vec,
+	}
+	h.baseStatsHandler = baseStatsHandler{
+		collector: vec,
+		options: statsHandlerOptions{
+			handleRPCLabelFn: h.labels,
+		},
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ClientMetadataReceivedSizeStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	switch pay := stat.(type) {
+	case *stats.InHeader:
+		if stat.IsClient() {
+			h.observe(ctx, h.vec, h.labelValues(ctx, stat), float64(metadataSize(pay.Header)))
+		}
+	case *stats.InTrailer:
+		if stat.IsClient() {
+			h.observe(ctx, h.vec, h.labelValues(ctx, stat), float64(metadataSize(pay.Trailer)))
+		}
+	case *stats.OutHeader:
+		_ = h.uas.ClientSide(ctx, pay)
+	}
+}
+
+func (h *ClientMetadataReceivedSizeStatsHandler) labels(ctx context.Context, stat stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	return []string{
+		tag.isFailFast,
+		metadataKind(stat),
+		tag.method,
+		tag.service,
+		h.uas.ClientSide(ctx, stat),
+	}
+}
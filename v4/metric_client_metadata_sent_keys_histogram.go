@@ -0,0 +1,66 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/piotrkowalczuk/promgrpc/v4/internal/useragent"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewClientMetadataSentKeysHistogramVec allocates a new Prometheus HistogramVec for the client and given set of options.
+func NewClientMetadataSentKeysHistogramVec(opts ...CollectorOption) *prometheus.HistogramVec {
+	labels := []string{
+		labelIsFailFast,
+		labelMetadataKind,
+		labelMethod,
+		labelService,
+		labelClientUserAgent,
+	}
+	return newMetadataSentKeysHistogramVec("client", labels, opts...)
+}
+
+// ClientMetadataSentKeysStatsHandler is responsible for observing the number of metadata keys sent within request headers.
+type ClientMetadataSentKeysStatsHandler struct {
+	baseStatsHandler
+	uas useragent.Store
+	vec prometheus.ObserverVec
+}
+
+// NewClientMetadataSentKeysStatsHandler ...
+func NewClientMetadataSentKeysStatsHandler(vec prometheus.ObserverVec, opts ...StatsHandlerOption) *ClientMetadataSentKeysStatsHandler {
+	h := &ClientMetadataSentKeysStatsHandler{
+		vec: vec,
+	}
+	h.baseStatsHandler = baseStatsHandler{
+		collector: vec,
+		options: statsHandlerOptions{
+			handleRPCLabelFn: h.labels,
+		},
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ClientMetadataSentKeysStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	switch pay := stat.(type) {
+	case *stats.OutHeader:
+		if stat.IsClient() {
+			h.observe(ctx, h.vec, h.labelValues(ctx, stat), float64(len(pay.Header)))
+		}
+	}
+}
+
+func (h *ClientMetadataSentKeysStatsHandler) labels(ctx context.Context, stat stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	return []string{
+		tag.isFailFast,
+		metadataKind(stat),
+		tag.method,
+		tag.service,
+		h.uas.ClientSide(ctx, stat),
+	}
+}
@@ -0,0 +1,69 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/piotrkowalczuk/promgrpc/v4/internal/useragent"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewClientMetadataSentSizeHistogramVec allocates a new Prometheus HistogramVec for the client and given set of options.
+func NewClientMetadataSentSizeHistogramVec(opts ...CollectorOption) *prometheus.HistogramVec {
+	labels := []string{
+		labelIsFailFast,
+		labelMetadataKind,
+		labelMethod,
+		labelService,
+		labelClientUserAgent,
+	}
+	return newMetadataSentSizeHistogramVec("client", labels, opts...)
+}
+
+// ClientMetadataSentSizeStatsHandler is responsible for observing the size of outgoing request headers.
+// The transport does not report their wire length, hence the size is estimated the same way HTTP/2 computes a header list size.
+// It is the size that SETTINGS_MAX_HEADER_LIST_SIZE limit applies to.
+// Large metadata, e.g. authentication tokens or tracing baggage, can lead to header list size limit violations.
+type ClientMetadataSentSizeStatsHandler struct {
+	baseStatsHandler
+	uas useragent.Store
+	vec prometheus.ObserverVec
+}
+
+// NewClientMetadataSentSizeStatsHandler ...
+func NewClientMetadataSentSizeStatsHandler(vec prometheus.ObserverVec, opts ...StatsHandlerOption) *ClientMetadataSentSizeStatsHandler {
+	h := &ClientMetadataSentSizeStatsHandler{
+		vec: vec,
+	}
+	h.baseStatsHandler = baseStatsHandler{
+		collector: vec,
+		options: statsHandlerOptions{
+			handleRPCLabelFn: h.labels,
+		},
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ClientMetadataSentSizeStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	switch pay := stat.(type) {
+	case *stats.OutHeader:
+		if stat.IsClient() {
+			h.observe(ctx, h.vec, h.labelValues(ctx, stat), float64(metadataSize(pay.Header)))
+		}
+	}
+}
+
+func (h *ClientMetadataSentSizeStatsHandler) labels(ctx context.Context, stat stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	return []string{
+		tag.isFailFast,
+		metadataKind(stat),
+		tag.method,
+		tag.service,
+		h.uas.ClientSide(ctx, stat),
+	}
+}
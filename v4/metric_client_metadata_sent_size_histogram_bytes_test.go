@@ -0,0 +1,57 @@
+package promgrpc_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+)
+
+func TestNewClientMetadataSentSizeStatsHandler(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.NewStatsHandler(promgrpc.NewClientMetadataSentSizeStatsHandler(promgrpc.NewClientMetadataSentSizeHistogramVec()))
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+		FullMethodName: "/service/Method",
+		FailFast:       true,
+	})
+	h.HandleRPC(ctx, &stats.OutHeader{
+		Client: true,
+		Header: metadata.MD{"user-agent": []string{"fake-user-agent"}},
+	})
+	h.HandleRPC(ctx, &stats.OutHeader{
+		Header: metadata.MD{"x-a": []string{"b"}},
+	})
+
+	const metadata = `
+		# HELP grpc_client_metadata_sent_size_histogram_bytes Size of headers and trailers sent, computed as an HTTP/2 header list size.
+        # TYPE grpc_client_metadata_sent_size_histogram_bytes histogram
+	`
+	expected := `
+		grpc_client_metadata_sent_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_is_fail_fast="true",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service",le="32"} 0
+        grpc_client_metadata_sent_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_is_fail_fast="true",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service",le="64"} 1
+        grpc_client_metadata_sent_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_is_fail_fast="true",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service",le="128"} 1
+        grpc_client_metadata_sent_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_is_fail_fast="true",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service",le="256"} 1
+        grpc_client_metadata_sent_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_is_fail_fast="true",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service",le="512"} 1
+        grpc_client_metadata_sent_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_is_fail_fast="true",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service",le="1024"} 1
+        grpc_client_metadata_sent_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_is_fail_fast="true",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service",le="2048"} 1
+        grpc_client_metadata_sent_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_is_fail_fast="true",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service",le="4096"} 1
+        grpc_client_metadata_sent_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_is_fail_fast="true",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service",le="8192"} 1
+        grpc_client_metadata_sent_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_is_fail_fast="true",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service",le="16384"} 1
+        grpc_client_metadata_sent_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_is_fail_fast="true",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service",le="32768"} 1
+        grpc_client_metadata_sent_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_is_fail_fast="true",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service",le="65536"} 1
+        grpc_client_metadata_sent_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_is_fail_fast="true",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service",le="+Inf"} 1
+        grpc_client_metadata_sent_size_histogram_bytes_sum{grpc_client_user_agent="fake-user-agent",grpc_is_fail_fast="true",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service"} 57
+        grpc_client_metadata_sent_size_histogram_bytes_count{grpc_client_user_agent="fake-user-agent",grpc_is_fail_fast="true",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service"} 1
+	`
+
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_client_metadata_sent_size_histogram_bytes"); err != nil {
+		t.Fatal(err)
+	}
+}
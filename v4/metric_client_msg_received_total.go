@@ -0,0 +1,59 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewClientMsgReceivedCounterVec allocates a new Prometheus CounterVec for the client and given set of options.
+// It is compatible with go-grpc-prometheus.
+func NewClientMsgReceivedCounterVec(opts ...CollectorOption) *prometheus.CounterVec {
+	labels := []string{
+		// keep alphabetical order
+		labelMethod,
+		labelService,
+		labelType,
+	}
+	return newMsgReceivedTotalCounterVec("client", "Total number of RPC stream messages received by the client.", labels, opts...)
+}
+
+// ClientMsgReceivedStatsHandler is responsible for counting messages received by the client, the same way go-grpc-prometheus does (grpc_client_msg_received_total).
+type ClientMsgReceivedStatsHandler struct {
+	baseStatsHandler
+	vec *prometheus.CounterVec
+}
+
+// NewClientMsgReceivedStatsHandler ...
+func NewClientMsgReceivedStatsHandler(vec *prometheus.CounterVec, opts ...StatsHandlerOption) *ClientMsgReceivedStatsHandler {
+	h := &ClientMsgReceivedStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+			options: statsHandlerOptions{
+				handleRPCLabelFn: clientMsgReceivedLabels,
+			},
+		},
+		vec: vec,
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ClientMsgReceivedStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if _, ok := stat.(*stats.InPayload); ok && stat.IsClient() {
+		h.inc(ctx, h.vec.WithLabelValues(h.labelValues(ctx, stat)...))
+	}
+}
+
+func clientMsgReceivedLabels(ctx context.Context, _ stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	// keep alphabetical order
+	return []string{
+		tag.method,
+		tag.service,
+		tag.rpcType,
+	}
+}
@@ -0,0 +1,59 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewClientMsgSentCounterVec allocates a new Prometheus CounterVec for the client and given set of options.
+// It is compatible with go-grpc-prometheus.
+func NewClientMsgSentCounterVec(opts ...CollectorOption) *prometheus.CounterVec {
+	labels := []string{
+		// keep alphabetical order
+		labelMethod,
+		labelService,
+		labelType,
+	}
+	return newMsgSentTotalCounterVec("client", "Total number of gRPC stream messages sent by the client.", labels, opts...)
+}
+
+// ClientMsgSentStatsHandler is responsible for counting messages sent by the client, the same way go-grpc-prometheus does (grpc_client_msg_sent_total).
+type ClientMsgSentStatsHandler struct {
+	baseStatsHandler
+	vec *prometheus.CounterVec
+}
+
+// NewClientMsgSentStatsHandler ...
+func NewClientMsgSentStatsHandler(vec *prometheus.CounterVec, opts ...StatsHandlerOption) *ClientMsgSentStatsHandler {
+	h := &ClientMsgSentStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+			options: statsHandlerOptions{
+				handleRPCLabelFn: clientMsgSentLabels,
+			},
+		},
+		vec: vec,
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ClientMsgSentStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if _, ok := stat.(*stats.OutPayload); ok && stat.IsClient() {
+		h.inc(ctx, h.vec.WithLabelValues(h.labelValues(ctx, stat)...))
+	}
+}
+
+func clientMsgSentLabels(ctx context.Context, _ stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	// keep alphabetical order
+	return []string{
+		tag.method,
+		tag.service,
+		tag.rpcType,
+	}
+}
@@ -0,0 +1,47 @@
+package promgrpc_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/stats"
+)
+
+func TestNewClientMsgSentStatsHandler(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.NewStatsHandler(promgrpc.NewClientMsgSentStatsHandler(promgrpc.NewClientMsgSentCounterVec()))
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+		FullMethodName: "/service/Method",
+		FailFast:       true,
+	})
+	h.HandleRPC(ctx, &stats.Begin{
+		Client:         true,
+		IsClientStream: true,
+		IsServerStream: true,
+	})
+	h.HandleRPC(ctx, &stats.OutPayload{
+		Client: true,
+	})
+	h.HandleRPC(ctx, &stats.OutPayload{
+		Client: true,
+	})
+	h.HandleRPC(ctx, &stats.OutPayload{})
+
+	const metadata = `
+		# HELP grpc_client_msg_sent_total Total number of gRPC stream messages sent by the client.
+        # TYPE grpc_client_msg_sent_total counter
+	`
+	expected := `
+		grpc_client_msg_sent_total{grpc_method="Method",grpc_service="service",grpc_type="bidi_stream"} 2
+	`
+
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_client_msg_sent_total"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,72 @@
+package promgrpc_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+)
+
+func TestNewClientPayloadReceivedPerRequestStatsHandler(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.NewStatsHandler(promgrpc.NewClientPayloadReceivedPerRequestStatsHandler(promgrpc.NewClientPayloadReceivedPerRequestHistogramVec()))
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+		FullMethodName: "/service/Method",
+		FailFast:       true,
+	})
+	h.HandleRPC(ctx, &stats.OutHeader{
+		Client: true,
+		Header: metadata.MD{"user-agent": []string{"fake-user-agent"}},
+	})
+	h.HandleRPC(ctx, &stats.InPayload{
+		Client: true,
+		Length: 100,
+	})
+	h.HandleRPC(ctx, &stats.InPayload{
+		Client: true,
+		Length: 200,
+	})
+	h.HandleRPC(ctx, &stats.InPayload{
+		Client: true,
+		Length: 300,
+	})
+	h.HandleRPC(ctx, &stats.OutPayload{
+		Client: true,
+		Length: 1000,
+	})
+	h.HandleRPC(ctx, &stats.End{
+		Client: true,
+	})
+	h.HandleRPC(ctx, &stats.End{})
+
+	const metadata = `
+		# HELP grpc_client_payload_received_per_request_histogram_bytes Total size of messages received within a single RPC.
+        # TYPE grpc_client_payload_received_per_request_histogram_bytes histogram
+	`
+	expected := `
+		grpc_client_payload_received_per_request_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="64"} 0
+        grpc_client_payload_received_per_request_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="256"} 0
+        grpc_client_payload_received_per_request_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="1024"} 1
+        grpc_client_payload_received_per_request_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="4096"} 1
+        grpc_client_payload_received_per_request_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="16384"} 1
+        grpc_client_payload_received_per_request_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="65536"} 1
+        grpc_client_payload_received_per_request_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="262144"} 1
+        grpc_client_payload_received_per_request_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="1048576"} 1
+        grpc_client_payload_received_per_request_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="4194304"} 1
+        grpc_client_payload_received_per_request_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="16777216"} 1
+        grpc_client_payload_received_per_request_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="+Inf"} 1
+        grpc_client_payload_received_per_request_histogram_bytes_sum{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service"} 600
+        grpc_client_payload_received_per_request_histogram_bytes_count{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service"} 1
+	`
+
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_client_payload_received_per_request_histogram_bytes"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,88 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/piotrkowalczuk/promgrpc/v4/internal/useragent"
+
+	"google.golang.org/grpc/status"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewClientPayloadSentPerRequestHistogramVec allocates a new Prometheus HistogramVec for the client and given set of options.
+func NewClientPayloadSentPerRequestHistogramVec(opts ...CollectorOption) *prometheus.HistogramVec {
+	labels := []string{
+		labelCode,
+		labelIsFailFast,
+		labelMethod,
+		labelService,
+		labelClientUserAgent,
+	}
+	return newPayloadSentPerRequestHistogramVec("client", labels, opts...)
+}
+
+// ClientPayloadSentPerRequestStatsHandler is responsible for observing the total size of messages sent within a single RPC.
+// The total is observed once the RPC ends.
+type ClientPayloadSentPerRequestStatsHandler struct {
+	baseStatsHandler
+	uas useragent.Store
+	vec prometheus.ObserverVec
+}
+
+// NewClientPayloadSentPerRequestStatsHandler ...
+func NewClientPayloadSentPerRequestStatsHandler(vec prometheus.ObserverVec, opts ...StatsHandlerOption) *ClientPayloadSentPerRequestStatsHandler {
+	h := &ClientPayloadSentPerRequestStatsHandler{
+		vec: vec,
+	}
+	h.baseStatsHandler = baseStatsHandler{
+		collector: vec,
+		options: statsHandlerOptions{
+			handleRPCLabelFn: h.labels,
+		},
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// TagRPC implements stats Handler interface.
+func (h *ClientPayloadSentPerRequestStatsHandler) TagRPC(ctx context.Context, inf *stats.RPCTagInfo) context.Context {
+	ctx = h.baseStatsHandler.TagRPC(ctx, inf)
+	ctx = context.WithValue(ctx, clientPayloadSentPerRequestKey{}, &requestTotalMark{})
+	return ctx
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ClientPayloadSentPerRequestStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if !stat.IsClient() {
+		return
+	}
+
+	switch pay := stat.(type) {
+	case *stats.OutPayload:
+		if mrk, ok := ctx.Value(clientPayloadSentPerRequestKey{}).(*requestTotalMark); ok {
+			mrk.add(int64(pay.Length))
+		}
+	case *stats.End:
+		if mrk, ok := ctx.Value(clientPayloadSentPerRequestKey{}).(*requestTotalMark); ok {
+			h.observe(ctx, h.vec, h.labelValues(ctx, stat), float64(mrk.load()))
+		}
+	case *stats.OutHeader:
+		_ = h.uas.ClientSide(ctx, pay)
+	}
+}
+
+func (h *ClientPayloadSentPerRequestStatsHandler) labels(ctx context.Context, stat stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	return []string{
+		status.Code(stat.(*stats.End).Error).String(),
+		tag.isFailFast,
+		tag.method,
+		tag.service,
+		h.uas.ClientSide(ctx, stat),
+	}
+}
+
+type clientPayloadSentPerRequestKey struct{}
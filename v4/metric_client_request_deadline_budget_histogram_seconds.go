@@ -0,0 +1,61 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewClientRequestDeadlineBudgetHistogramVec allocates a new Prometheus HistogramVec for the client and given set of options.
+func NewClientRequestDeadlineBudgetHistogramVec(opts ...CollectorOption) *prometheus.HistogramVec {
+	labels := []string{
+		labelIsFailFast,
+		labelMethod,
+		labelService,
+	}
+	return newRequestDeadlineBudgetHistogramVec("client", labels, opts...)
+}
+
+// ClientRequestDeadlineBudgetStatsHandler is responsible for observing the timeout an outgoing RPC was configured with.
+// It is measured as time left until the deadline when the RPC begins.
+// If the RPC is retried, each attempt is observed with the budget that was left for it.
+// RPCs without a deadline are not observed, see ClientRequestsWithoutDeadlineTotalStatsHandler.
+type ClientRequestDeadlineBudgetStatsHandler struct {
+	baseStatsHandler
+	vec prometheus.ObserverVec
+}
+
+// NewClientRequestDeadlineBudgetStatsHandler ...
+func NewClientRequestDeadlineBudgetStatsHandler(vec prometheus.ObserverVec, opts ...StatsHandlerOption) *ClientRequestDeadlineBudgetStatsHandler {
+	h := &ClientRequestDeadlineBudgetStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+			options: statsHandlerOptions{
+				handleRPCLabelFn: clientRequestDeadlineBudgetLabels,
+			},
+		},
+		vec: vec,
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ClientRequestDeadlineBudgetStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if pay, ok := stat.(*stats.Begin); ok && stat.IsClient() {
+		if budget, ok := remaining(ctx, pay.BeginTime); ok {
+			h.observe(ctx, h.vec, h.labelValues(ctx, stat), budget.Seconds())
+		}
+	}
+}
+
+func clientRequestDeadlineBudgetLabels(ctx context.Context, _ stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	return []string{
+		tag.isFailFast,
+		tag.method,
+		tag.service,
+	}
+}
@@ -0,0 +1,59 @@
+package promgrpc_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/stats"
+)
+
+func TestNewClientRequestDeadlineBudgetStatsHandler(t *testing.T) {
+	deadline := time.Now().Add(time.Minute)
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	h := promgrpc.NewStatsHandler(promgrpc.NewClientRequestDeadlineBudgetStatsHandler(promgrpc.NewClientRequestDeadlineBudgetHistogramVec()))
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+		FullMethodName: "/service/Method",
+		FailFast:       true,
+	})
+	h.HandleRPC(ctx, &stats.Begin{
+		Client:    true,
+		BeginTime: deadline.Add(-5 * time.Second),
+	})
+	h.HandleRPC(ctx, &stats.Begin{
+		BeginTime: deadline.Add(-5 * time.Second),
+	})
+
+	const metadata = `
+		# HELP grpc_client_request_deadline_budget_histogram_seconds Time left until the deadline of an RPC when it began.
+        # TYPE grpc_client_request_deadline_budget_histogram_seconds histogram
+	`
+	expected := `
+		grpc_client_request_deadline_budget_histogram_seconds_bucket{grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="0.005"} 0
+        grpc_client_request_deadline_budget_histogram_seconds_bucket{grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="0.01"} 0
+        grpc_client_request_deadline_budget_histogram_seconds_bucket{grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="0.025"} 0
+        grpc_client_request_deadline_budget_histogram_seconds_bucket{grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="0.05"} 0
+        grpc_client_request_deadline_budget_histogram_seconds_bucket{grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="0.1"} 0
+        grpc_client_request_deadline_budget_histogram_seconds_bucket{grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="0.25"} 0
+        grpc_client_request_deadline_budget_histogram_seconds_bucket{grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="0.5"} 0
+        grpc_client_request_deadline_budget_histogram_seconds_bucket{grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="1"} 0
+        grpc_client_request_deadline_budget_histogram_seconds_bucket{grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="2.5"} 0
+        grpc_client_request_deadline_budget_histogram_seconds_bucket{grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="5"} 1
+        grpc_client_request_deadline_budget_histogram_seconds_bucket{grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="10"} 1
+        grpc_client_request_deadline_budget_histogram_seconds_bucket{grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="30"} 1
+        grpc_client_request_deadline_budget_histogram_seconds_bucket{grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="60"} 1
+        grpc_client_request_deadline_budget_histogram_seconds_bucket{grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="300"} 1
+        grpc_client_request_deadline_budget_histogram_seconds_bucket{grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="+Inf"} 1
+        grpc_client_request_deadline_budget_histogram_seconds_sum{grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service"} 5
+        grpc_client_request_deadline_budget_histogram_seconds_count{grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service"} 1
+	`
+
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_client_request_deadline_budget_histogram_seconds"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,76 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/piotrkowalczuk/promgrpc/v4/internal/useragent"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+)
+
+// NewClientRequestDeadlineHeadroomHistogramVec allocates a new Prometheus HistogramVec for the client and given set of options.
+func NewClientRequestDeadlineHeadroomHistogramVec(opts ...CollectorOption) *prometheus.HistogramVec {
+	labels := []string{
+		labelCode,
+		labelIsFailFast,
+		labelMethod,
+		labelService,
+		labelClientUserAgent,
+	}
+	return newRequestDeadlineHeadroomHistogramVec("client", labels, opts...)
+}
+
+// ClientRequestDeadlineHeadroomStatsHandler is responsible for observing how much time was left until the deadline of an outgoing RPC when it ended.
+// Headroom close to zero means that the deadline is too tight for a given method.
+// RPCs that ended past their deadline are observed as zero.
+// RPCs without a deadline are not observed, see ClientRequestsWithoutDeadlineTotalStatsHandler.
+type ClientRequestDeadlineHeadroomStatsHandler struct {
+	baseStatsHandler
+	uas useragent.Store
+	vec prometheus.ObserverVec
+}
+
+// NewClientRequestDeadlineHeadroomStatsHandler ...
+func NewClientRequestDeadlineHeadroomStatsHandler(vec prometheus.ObserverVec, opts ...StatsHandlerOption) *ClientRequestDeadlineHeadroomStatsHandler {
+	h := &ClientRequestDeadlineHeadroomStatsHandler{
+		vec: vec,
+	}
+	h.baseStatsHandler = baseStatsHandler{
+		collector: vec,
+		options: statsHandlerOptions{
+			handleRPCLabelFn: h.labels,
+		},
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ClientRequestDeadlineHeadroomStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if !stat.IsClient() {
+		return
+	}
+
+	switch pay := stat.(type) {
+	case *stats.End:
+		if headroom, ok := remaining(ctx, pay.EndTime); ok {
+			h.observe(ctx, h.vec, h.labelValues(ctx, stat), headroom.Seconds())
+		}
+	case *stats.OutHeader:
+		_ = h.uas.ClientSide(ctx, pay)
+	}
+}
+
+func (h *ClientRequestDeadlineHeadroomStatsHandler) labels(ctx context.Context, stat stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	return []string{
+		status.Code(stat.(*stats.End).Error).String(),
+		tag.isFailFast,
+		tag.method,
+		tag.service,
+		h.uas.ClientSide(ctx, stat),
+	}
+}
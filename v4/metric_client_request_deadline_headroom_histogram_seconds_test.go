@@ -0,0 +1,88 @@
+package promgrpc_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+)
+
+func TestNewClientRequestDeadlineHeadroomStatsHandler(t *testing.T) {
+	deadline := time.Now().Add(time.Minute)
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	h := promgrpc.NewStatsHandler(promgrpc.NewClientRequestDeadlineHeadroomStatsHandler(promgrpc.NewClientRequestDeadlineHeadroomHistogramVec()))
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+		FullMethodName: "/service/Method",
+		FailFast:       true,
+	})
+	h.HandleRPC(ctx, &stats.OutHeader{
+		Client: true,
+		Header: metadata.MD{"user-agent": []string{"fake-user-agent"}},
+	})
+	h.HandleRPC(ctx, &stats.End{
+		Client:  true,
+		EndTime: deadline.Add(-500 * time.Millisecond),
+	})
+	h.HandleRPC(ctx, &stats.End{
+		Client:  true,
+		EndTime: deadline.Add(time.Second),
+		Error:   status.Error(codes.DeadlineExceeded, "context deadline exceeded"),
+	})
+	h.HandleRPC(ctx, &stats.End{
+		EndTime: deadline.Add(-500 * time.Millisecond),
+	})
+
+	const metadata = `
+		# HELP grpc_client_request_deadline_headroom_histogram_seconds Time left until the deadline of an RPC when it ended.
+        # TYPE grpc_client_request_deadline_headroom_histogram_seconds histogram
+	`
+	expected := `
+		grpc_client_request_deadline_headroom_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="DeadlineExceeded",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="0.005"} 1
+        grpc_client_request_deadline_headroom_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="DeadlineExceeded",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="0.01"} 1
+        grpc_client_request_deadline_headroom_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="DeadlineExceeded",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="0.025"} 1
+        grpc_client_request_deadline_headroom_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="DeadlineExceeded",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="0.05"} 1
+        grpc_client_request_deadline_headroom_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="DeadlineExceeded",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="0.1"} 1
+        grpc_client_request_deadline_headroom_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="DeadlineExceeded",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="0.25"} 1
+        grpc_client_request_deadline_headroom_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="DeadlineExceeded",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="0.5"} 1
+        grpc_client_request_deadline_headroom_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="DeadlineExceeded",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="1"} 1
+        grpc_client_request_deadline_headroom_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="DeadlineExceeded",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="2.5"} 1
+        grpc_client_request_deadline_headroom_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="DeadlineExceeded",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="5"} 1
+        grpc_client_request_deadline_headroom_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="DeadlineExceeded",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="10"} 1
+        grpc_client_request_deadline_headroom_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="DeadlineExceeded",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="30"} 1
+        grpc_client_request_deadline_headroom_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="DeadlineExceeded",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="60"} 1
+        grpc_client_request_deadline_headroom_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="DeadlineExceeded",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="300"} 1
+        grpc_client_request_deadline_headroom_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="DeadlineExceeded",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="+Inf"} 1
+        grpc_client_request_deadline_headroom_histogram_seconds_sum{grpc_client_user_agent="fake-user-agent",grpc_code="DeadlineExceeded",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service"} 0
+        grpc_client_request_deadline_headroom_histogram_seconds_count{grpc_client_user_agent="fake-user-agent",grpc_code="DeadlineExceeded",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service"} 1
+        grpc_client_request_deadline_headroom_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="0.005"} 0
+        grpc_client_request_deadline_headroom_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="0.01"} 0
+        grpc_client_request_deadline_headroom_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="0.025"} 0
+        grpc_client_request_deadline_headroom_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="0.05"} 0
+        grpc_client_request_deadline_headroom_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="0.1"} 0
+        grpc_client_request_deadline_headroom_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="0.25"} 0
+        grpc_client_request_deadline_headroom_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="0.5"} 1
+        grpc_client_request_deadline_headroom_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="1"} 1
+        grpc_client_request_deadline_headroom_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="2.5"} 1
+        grpc_client_request_deadline_headroom_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="5"} 1
+        grpc_client_request_deadline_headroom_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="10"} 1
+        grpc_client_request_deadline_headroom_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="30"} 1
+        grpc_client_request_deadline_headroom_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="60"} 1
+        grpc_client_request_deadline_headroom_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="300"} 1
+        grpc_client_request_deadline_headroom_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",le="+Inf"} 1
+        grpc_client_request_deadline_headroom_histogram_seconds_sum{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service"} 0.5
+        grpc_client_request_deadline_headroom_histogram_seconds_count{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service"} 1
+	`
+
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_client_request_deadline_headroom_histogram_seconds"); err != nil {
+		t.Fatal(err)
+	}
+}
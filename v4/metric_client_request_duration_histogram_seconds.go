@@ -34,7 +34,8 @@ func NewClientRequestDurationStatsHandler(vec prometheus.ObserverVec, opts ...St
 		vec: vec,
 	}
 	h.baseStatsHandler = baseStatsHandler{
-		collector: vec,
+		collector:       vec,
+		supportedLabels: optionalLabelType,
 		options: statsHandlerOptions{
 			handleRPCLabelFn: h.labels,
 		},
@@ -49,9 +50,7 @@ func (h *ClientRequestDurationStatsHandler) HandleRPC(ctx context.Context, stat
 	switch pay := stat.(type) {
 	case *stats.End:
 		if stat.IsClient() {
-			h.vec.
-				WithLabelValues(h.options.handleRPCLabelFn(ctx, stat)...).
-				Observe(pay.EndTime.Sub(pay.BeginTime).Seconds())
+			h.observe(ctx, h.vec, h.labelValues(ctx, stat), pay.EndTime.Sub(pay.BeginTime).Seconds())
 		}
 	case *stats.OutHeader:
 		_ = h.uas.ClientSide(ctx, pay)
@@ -59,7 +58,7 @@ func (h *ClientRequestDurationStatsHandler) HandleRPC(ctx context.Context, stat
 }
 
 func (h *ClientRequestDurationStatsHandler) labels(ctx context.Context, stat stats.RPCStats) []string {
-	tag := ctx.Value(tagRPCKey).(rpcTagLabels)
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
 	return []string{
 		status.Code(stat.(*stats.End).Error).String(),
 		tag.isFailFast,
@@ -0,0 +1,14 @@
+package promgrpc
+
+// NewClientRequestDurationSummaryVec allocates a new SketchSummaryVec for the client and given set of options.
+// It is an alternative to NewClientRequestDurationHistogramVec, meant to be passed to NewClientRequestDurationStatsHandler.
+func NewClientRequestDurationSummaryVec(opts ...CollectorOption) *SketchSummaryVec {
+	labels := []string{
+		labelCode,
+		labelIsFailFast,
+		labelMethod,
+		labelService,
+		labelClientUserAgent,
+	}
+	return newRequestDurationSummaryVec("client", labels, opts...)
+}
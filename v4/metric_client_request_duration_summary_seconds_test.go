@@ -0,0 +1,53 @@
+package promgrpc_test
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/piotrkowalczuk/promgrpc/v4/internal/testutil"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+func TestNewClientRequestDurationSummaryVec(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.NewStatsHandler(promgrpc.NewClientRequestDurationStatsHandler(
+		promgrpc.NewClientRequestDurationSummaryVec(promgrpc.CollectorWithSummaryQuantiles(0.99)),
+	))
+	reg := prometheus.NewRegistry()
+	registerCollector(t, reg, h)
+
+	bt := time.Now()
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+		FullMethodName: "/service/Method",
+		FailFast:       true,
+	})
+	h.HandleRPC(ctx, &stats.Begin{
+		Client: true,
+	})
+	h.HandleRPC(ctx, &stats.End{
+		Client:    true,
+		BeginTime: bt,
+		EndTime:   bt.Add(2 * time.Minute),
+	})
+
+	testutil.AssertMetricValue(t, reg, "grpc_client_request_duration_summary_seconds_sum", 120)
+	testutil.AssertMetricValue(t, reg, "grpc_client_request_duration_summary_seconds_count", 1)
+
+	mf, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	quantiles := mf[0].GetMetric()[0].GetSummary().GetQuantile()
+	if len(quantiles) != 1 {
+		t.Fatalf("expected 1 quantile, but got %d", len(quantiles))
+	}
+	if got := quantiles[0].GetValue(); math.Abs(got-120) > 120*promgrpc.DefSketchRelativeAccuracy {
+		t.Fatalf("expected 120 ±1%%, but got %g", got)
+	}
+}
@@ -0,0 +1,94 @@
+package promgrpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4/internal/useragent"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewClientRequestPhaseDurationHistogramVec allocates a new Prometheus HistogramVec for the client and given set of options.
+func NewClientRequestPhaseDurationHistogramVec(opts ...CollectorOption) *prometheus.HistogramVec {
+	labels := []string{
+		labelIsFailFast,
+		labelMethod,
+		labelPhase,
+		labelService,
+		labelClientUserAgent,
+	}
+	return newRequestPhaseDurationHistogramVec("client", labels, opts...)
+}
+
+// ClientRequestPhaseDurationStatsHandler is responsible for measuring how long it takes for an outgoing RPC to receive the first response headers and the first response message.
+// Each phase is observed once per RPC, as soon as it is reached.
+// Unlike ClientRequestDurationStatsHandler, it makes it possible to tell a backend that is slow to start answering apart from a long-lived stream.
+type ClientRequestPhaseDurationStatsHandler struct {
+	baseStatsHandler
+	uas useragent.Store
+	vec prometheus.ObserverVec
+}
+
+// NewClientRequestPhaseDurationStatsHandler ...
+func NewClientRequestPhaseDurationStatsHandler(vec prometheus.ObserverVec, opts ...StatsHandlerOption) *ClientRequestPhaseDurationStatsHandler {
+	h := &ClientRequestPhaseDurationStatsHandler{
+		vec: vec,
+	}
+	h.baseStatsHandler = baseStatsHandler{
+		collector: vec,
+		options: statsHandlerOptions{
+			handleRPCLabelFn: h.labels,
+		},
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// TagRPC implements stats Handler interface.
+func (h *ClientRequestPhaseDurationStatsHandler) TagRPC(ctx context.Context, inf *stats.RPCTagInfo) context.Context {
+	ctx = h.baseStatsHandler.TagRPC(ctx, inf)
+	ctx = context.WithValue(ctx, clientRequestPhaseKey{}, &requestPhaseMark{})
+	return ctx
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ClientRequestPhaseDurationStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if !stat.IsClient() {
+		return
+	}
+	mrk, ok := ctx.Value(clientRequestPhaseKey{}).(*requestPhaseMark)
+	if !ok {
+		return
+	}
+
+	switch pay := stat.(type) {
+	case *stats.Begin:
+		mrk.begin(pay.BeginTime)
+	case *stats.OutHeader:
+		_ = h.uas.ClientSide(ctx, pay)
+	case *stats.InHeader:
+		if elapsed, ok := mrk.reach(phaseInHeader, time.Now()); ok {
+			h.observe(ctx, h.vec, h.labelValues(ctx, stat), elapsed.Seconds())
+		}
+	case *stats.InPayload:
+		if elapsed, ok := mrk.reach(phaseFirstInPayload, pay.RecvTime); ok {
+			h.observe(ctx, h.vec, h.labelValues(ctx, stat), elapsed.Seconds())
+		}
+	}
+}
+
+func (h *ClientRequestPhaseDurationStatsHandler) labels(ctx context.Context, stat stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	return []string{
+		tag.isFailFast,
+		tag.method,
+		requestPhase(stat),
+		tag.service,
+		h.uas.ClientSide(ctx, stat),
+	}
+}
+
+type clientRequestPhaseKey struct{}
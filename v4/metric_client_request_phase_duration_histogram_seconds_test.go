@@ -0,0 +1,84 @@
+package promgrpc_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/piotrkowalczuk/promgrpc/v4/internal/testutil"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+)
+
+func TestNewClientRequestPhaseDurationStatsHandler(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	bt := time.Now()
+
+	h := promgrpc.NewStatsHandler(promgrpc.NewClientRequestPhaseDurationStatsHandler(promgrpc.NewClientRequestPhaseDurationHistogramVec()))
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+		FullMethodName: "/service/Method",
+		FailFast:       true,
+	})
+	h.HandleRPC(ctx, &stats.Begin{
+		Client:    true,
+		BeginTime: bt,
+	})
+	h.HandleRPC(ctx, &stats.OutHeader{
+		Client: true,
+		Header: metadata.MD{"user-agent": []string{"fake-user-agent"}},
+	})
+	h.HandleRPC(ctx, &stats.InHeader{
+		Client: true,
+	})
+	h.HandleRPC(ctx, &stats.InHeader{
+		Client: true,
+	})
+	h.HandleRPC(ctx, &stats.InPayload{
+		Client:   true,
+		RecvTime: bt.Add(10 * time.Second),
+	})
+	h.HandleRPC(ctx, &stats.InPayload{
+		Client:   true,
+		RecvTime: bt.Add(20 * time.Second),
+	})
+	h.HandleRPC(ctx, &stats.InPayload{
+		RecvTime: bt.Add(30 * time.Second),
+	})
+
+	reg := prometheus.NewRegistry()
+	registerCollector(t, reg, h)
+
+	testutil.AssertMetricValue(t, reg, "grpc_client_request_phase_duration_histogram_seconds_count", 2)
+	for _, phase := range []string{"in_header", "first_in_payload"} {
+		testutil.AssertMetricDimensions(t, reg, "grpc_client_request_phase_duration_histogram_seconds_count", map[string]string{
+			"grpc_client_user_agent": "fake-user-agent",
+			"grpc_is_fail_fast":      "true",
+			"grpc_method":            "Method",
+			"grpc_phase":             phase,
+			"grpc_service":           "service",
+		})
+	}
+	mf, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found bool
+	for _, m := range mf[0].GetMetric() {
+		for _, l := range m.GetLabel() {
+			if l.GetName() != "grpc_phase" || l.GetValue() != "first_in_payload" {
+				continue
+			}
+			found = true
+			if got := m.GetHistogram().GetSampleSum(); got != 10 {
+				t.Errorf("wrong time to first message, expected 10 but got %g", got)
+			}
+		}
+	}
+	if !found {
+		t.Error("time to first message not observed")
+	}
+}
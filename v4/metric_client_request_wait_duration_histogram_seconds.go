@@ -0,0 +1,101 @@
+package promgrpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4/internal/useragent"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewClientRequestWaitDurationHistogramVec allocates a new Prometheus HistogramVec for the client and given set of options.
+func NewClientRequestWaitDurationHistogramVec(opts ...CollectorOption) *prometheus.HistogramVec {
+	labels := []string{
+		labelIsFailFast,
+		labelMethod,
+		labelService,
+		labelClientUserAgent,
+	}
+	return newRequestWaitDurationHistogramVec("client", labels, opts...)
+}
+
+// ClientRequestWaitDurationStatsHandler is responsible for measuring how long an outgoing RPC waits before it reaches the wire.
+// It is the time between the beginning of an RPC and the moment its headers are sent.
+// It includes name resolution, load balancer picks and connection establishment,
+// which is especially noticeable for wait-for-ready (non fail-fast) RPCs.
+// An RPC that ends before its headers are sent (e.g. its deadline is exceeded while it waits for a transport)
+// is observed once it ends, so that the time it waited is not lost.
+type ClientRequestWaitDurationStatsHandler struct {
+	baseStatsHandler
+	uas useragent.Store
+	vec prometheus.ObserverVec
+}
+
+// NewClientRequestWaitDurationStatsHandler ...
+func NewClientRequestWaitDurationStatsHandler(vec prometheus.ObserverVec, opts ...StatsHandlerOption) *ClientRequestWaitDurationStatsHandler {
+	h := &ClientRequestWaitDurationStatsHandler{
+		vec: vec,
+	}
+	h.baseStatsHandler = baseStatsHandler{
+		collector: vec,
+		options: statsHandlerOptions{
+			handleRPCLabelFn: h.labels,
+		},
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// TagRPC implements stats Handler interface.
+func (h *ClientRequestWaitDurationStatsHandler) TagRPC(ctx context.Context, inf *stats.RPCTagInfo) context.Context {
+	ctx = h.baseStatsHandler.TagRPC(ctx, inf)
+	ctx = context.WithValue(ctx, clientRequestWaitKey{}, &clientRequestWaitMark{})
+	return ctx
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ClientRequestWaitDurationStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if !stat.IsClient() {
+		return
+	}
+	mrk, ok := ctx.Value(clientRequestWaitKey{}).(*clientRequestWaitMark)
+	if !ok {
+		return
+	}
+
+	switch pay := stat.(type) {
+	case *stats.Begin:
+		mrk.begin = pay.BeginTime
+	case *stats.OutHeader:
+		if !mrk.begin.IsZero() && !mrk.reached {
+			mrk.reached = true
+			h.observe(ctx, h.vec, h.labelValues(ctx, stat), time.Since(mrk.begin).Seconds())
+		}
+	case *stats.End:
+		if !mrk.begin.IsZero() && !mrk.reached {
+			mrk.reached = true
+			h.observe(ctx, h.vec, h.labelValues(ctx, stat), pay.EndTime.Sub(mrk.begin).Seconds())
+		}
+	}
+}
+
+func (h *ClientRequestWaitDurationStatsHandler) labels(ctx context.Context, stat stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	return []string{
+		tag.isFailFast,
+		tag.method,
+		tag.service,
+		h.uas.ClientSide(ctx, stat),
+	}
+}
+
+type clientRequestWaitKey struct{}
+
+type clientRequestWaitMark struct {
+	begin time.Time
+	// reached is true once the wait is observed, either when headers are sent or when the RPC ends.
+	reached bool
+}
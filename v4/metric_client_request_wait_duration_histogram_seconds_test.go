@@ -0,0 +1,96 @@
+package promgrpc_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/piotrkowalczuk/promgrpc/v4/internal/testutil"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+)
+
+func TestNewClientRequestWaitDurationStatsHandler(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.NewStatsHandler(promgrpc.NewClientRequestWaitDurationStatsHandler(promgrpc.NewClientRequestWaitDurationHistogramVec()))
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+		FullMethodName: "/service/Method",
+		FailFast:       false,
+	})
+	h.HandleRPC(ctx, &stats.Begin{
+		Client:    true,
+		BeginTime: time.Now().Add(-5 * time.Second),
+	})
+	h.HandleRPC(ctx, &stats.OutHeader{
+		Client: true,
+		Header: metadata.MD{"user-agent": []string{"fake-user-agent"}},
+	})
+	h.HandleRPC(ctx, &stats.OutHeader{
+		Client: false,
+	})
+	h.HandleRPC(ctx, &stats.End{
+		Client:  true,
+		EndTime: time.Now(),
+	})
+
+	reg := prometheus.NewRegistry()
+	registerCollector(t, reg, h)
+
+	testutil.AssertMetricValue(t, reg, "grpc_client_request_wait_duration_histogram_seconds_count", 1)
+	testutil.AssertMetricDimensions(t, reg, "grpc_client_request_wait_duration_histogram_seconds_count", map[string]string{
+		"grpc_client_user_agent": "fake-user-agent",
+		"grpc_is_fail_fast":      "false",
+		"grpc_method":            "Method",
+		"grpc_service":           "service",
+	})
+
+	mf, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := mf[0].GetMetric()[0].GetHistogram().GetSampleSum(); got < 5 {
+		t.Errorf("wait duration is too short, expected at least 5 but got %g", got)
+	}
+}
+
+func TestNewClientRequestWaitDurationStatsHandler_endWithoutHeaders(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.NewStatsHandler(promgrpc.NewClientRequestWaitDurationStatsHandler(promgrpc.NewClientRequestWaitDurationHistogramVec()))
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+		FullMethodName: "/service/Method",
+		FailFast:       false,
+	})
+	begin := time.Now().Add(-5 * time.Second)
+	h.HandleRPC(ctx, &stats.Begin{
+		Client:    true,
+		BeginTime: begin,
+	})
+	// Deadline exceeded while waiting for a transport, headers are never sent.
+	h.HandleRPC(ctx, &stats.End{
+		Client:    true,
+		BeginTime: begin,
+		EndTime:   begin.Add(5 * time.Second),
+		Error:     status.Error(codes.DeadlineExceeded, "context deadline exceeded"),
+	})
+
+	reg := prometheus.NewRegistry()
+	registerCollector(t, reg, h)
+
+	testutil.AssertMetricValue(t, reg, "grpc_client_request_wait_duration_histogram_seconds_count", 1)
+
+	mf, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := mf[0].GetMetric()[0].GetHistogram().GetSampleSum(); got != 5 {
+		t.Errorf("unexpected wait duration, expected 5 but got %g", got)
+	}
+}
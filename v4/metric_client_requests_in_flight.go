@@ -57,21 +57,21 @@ func (h *ClientRequestsInFlightStatsHandler) HandleRPC(ctx context.Context, stat
 		case stat.IsClient():
 			if mrk, ok := ctx.Value(clientRequestInFlightKey{}).(*clientRequestInFlightMark); ok {
 				mrk.started = true
-				h.vec.WithLabelValues(h.options.handleRPCLabelFn(ctx, stat)...).Inc()
+				h.vec.WithLabelValues(h.labelValues(ctx, stat)...).Inc()
 			}
 		}
 	case *stats.End:
 		switch {
 		case stat.IsClient():
 			if mrk, ok := ctx.Value(clientRequestInFlightKey{}).(*clientRequestInFlightMark); ok && mrk.started {
-				h.vec.WithLabelValues(h.options.handleRPCLabelFn(ctx, stat)...).Dec()
+				h.vec.WithLabelValues(h.labelValues(ctx, stat)...).Dec()
 			}
 		}
 	}
 }
 
 func (h *ClientRequestsInFlightStatsHandler) labels(ctx context.Context, stat stats.RPCStats) []string {
-	tag := ctx.Value(tagRPCKey).(rpcTagLabels)
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
 	// keep alphabetical order
 	return []string{
 		tag.isFailFast,
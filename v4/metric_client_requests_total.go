@@ -33,7 +33,8 @@ func NewClientRequestsTotalStatsHandler(vec *prometheus.CounterVec, opts ...Stat
 		vec: vec,
 	}
 	h.baseStatsHandler = baseStatsHandler{
-		collector: vec,
+		collector:       vec,
+		supportedLabels: optionalLabelType,
 		options: statsHandlerOptions{
 			handleRPCLabelFn: h.labels,
 		},
@@ -49,12 +50,12 @@ func (h *ClientRequestsTotalStatsHandler) HandleRPC(ctx context.Context, stat st
 		return
 	}
 	if _, ok := stat.(*stats.OutHeader); ok {
-		h.vec.WithLabelValues(h.options.handleRPCLabelFn(ctx, stat)...).Inc()
+		h.inc(ctx, h.vec.WithLabelValues(h.labelValues(ctx, stat)...))
 	}
 }
 
 func (h *ClientRequestsTotalStatsHandler) labels(ctx context.Context, sts stats.RPCStats) []string {
-	tag := ctx.Value(tagRPCKey).(rpcTagLabels)
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
 	return []string{
 		tag.isFailFast,
 		tag.method,
@@ -0,0 +1,59 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewClientRequestsWithoutDeadlineTotalCounterVec allocates a new Prometheus CounterVec for the client and given set of options.
+func NewClientRequestsWithoutDeadlineTotalCounterVec(opts ...CollectorOption) *prometheus.CounterVec {
+	labels := []string{
+		labelIsFailFast,
+		labelMethod,
+		labelService,
+	}
+	return newRequestsWithoutDeadlineTotalCounterVec("client", "requests_sent_without_deadline_total", "Number of RPCs sent without a deadline.", labels, opts...)
+}
+
+// ClientRequestsWithoutDeadlineTotalStatsHandler is responsible for counting outgoing RPCs that have no deadline set.
+// Such RPCs can wait for a response indefinitely.
+type ClientRequestsWithoutDeadlineTotalStatsHandler struct {
+	baseStatsHandler
+	vec *prometheus.CounterVec
+}
+
+// NewClientRequestsWithoutDeadlineTotalStatsHandler ...
+func NewClientRequestsWithoutDeadlineTotalStatsHandler(vec *prometheus.CounterVec, opts ...StatsHandlerOption) *ClientRequestsWithoutDeadlineTotalStatsHandler {
+	h := &ClientRequestsWithoutDeadlineTotalStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+			options: statsHandlerOptions{
+				handleRPCLabelFn: clientRequestsWithoutDeadlineTotalLabels,
+			},
+		},
+		vec: vec,
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ClientRequestsWithoutDeadlineTotalStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if _, ok := stat.(*stats.Begin); ok && stat.IsClient() {
+		if _, ok := ctx.Deadline(); !ok {
+			h.inc(ctx, h.vec.WithLabelValues(h.labelValues(ctx, stat)...))
+		}
+	}
+}
+
+func clientRequestsWithoutDeadlineTotalLabels(ctx context.Context, _ stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	return []string{
+		tag.isFailFast,
+		tag.method,
+		tag.service,
+	}
+}
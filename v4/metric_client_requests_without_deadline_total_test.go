@@ -0,0 +1,44 @@
+package promgrpc_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/stats"
+)
+
+func TestNewClientRequestsWithoutDeadlineTotalStatsHandler(t *testing.T) {
+	h := promgrpc.NewStatsHandler(promgrpc.NewClientRequestsWithoutDeadlineTotalStatsHandler(promgrpc.NewClientRequestsWithoutDeadlineTotalCounterVec()))
+
+	ctx := context.Background()
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+		FullMethodName: "/service/Method",
+		FailFast:       true,
+	})
+	h.HandleRPC(ctx, &stats.Begin{
+		Client: true,
+	})
+	h.HandleRPC(ctx, &stats.Begin{})
+
+	ctxWithDeadline, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	h.HandleRPC(ctxWithDeadline, &stats.Begin{
+		Client: true,
+	})
+
+	const metadata = `
+		# HELP grpc_client_requests_sent_without_deadline_total Number of RPCs sent without a deadline.
+        # TYPE grpc_client_requests_sent_without_deadline_total counter
+	`
+	expected := `
+		grpc_client_requests_sent_without_deadline_total{grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service"} 1
+	`
+
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_client_requests_sent_without_deadline_total"); err != nil {
+		t.Fatal(err)
+	}
+}
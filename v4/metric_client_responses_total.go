@@ -35,7 +35,8 @@ func NewClientResponsesTotalStatsHandler(vec *prometheus.CounterVec, opts ...Sta
 		vec: vec,
 	}
 	h.baseStatsHandler = baseStatsHandler{
-		collector: vec,
+		collector:       vec,
+		supportedLabels: optionalLabelType,
 		options: statsHandlerOptions{
 			handleRPCLabelFn: h.labels,
 		},
@@ -50,7 +51,7 @@ func (h *ClientResponsesTotalStatsHandler) HandleRPC(ctx context.Context, stat s
 	switch pay := stat.(type) {
 	case *stats.End:
 		if stat.IsClient() {
-			h.vec.WithLabelValues(h.options.handleRPCLabelFn(ctx, stat)...).Inc()
+			h.inc(ctx, h.vec.WithLabelValues(h.labelValues(ctx, stat)...))
 		}
 	case *stats.OutHeader:
 		_ = h.uas.ClientSide(ctx, pay)
@@ -58,7 +59,7 @@ func (h *ClientResponsesTotalStatsHandler) HandleRPC(ctx context.Context, stat s
 }
 
 func (h *ClientResponsesTotalStatsHandler) labels(ctx context.Context, stat stats.RPCStats) []string {
-	tag := ctx.Value(tagRPCKey).(rpcTagLabels)
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
 	return []string{
 		status.Code(stat.(*stats.End).Error).String(),
 		tag.isFailFast,
@@ -51,3 +51,43 @@ func TestNewClientResponsesTotalStatsHandler(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestNewClientResponsesTotalStatsHandler_typeLabel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.ClientStatsHandler(
+		promgrpc.WithTypeLabel(),
+	)
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+		FullMethodName: "/service/Method",
+		FailFast:       true,
+	})
+	h.HandleRPC(ctx, &stats.Begin{
+		Client:         true,
+		IsClientStream: true,
+		IsServerStream: true,
+	})
+	h.HandleRPC(ctx, &stats.OutHeader{
+		Client: true,
+		Header: metadata.MD{"user-agent": []string{"fake-user-agent"}},
+	})
+	h.HandleRPC(ctx, &stats.End{
+		Client: true,
+	})
+
+	const metadata = `
+		# HELP grpc_client_requests_sent_total TODO
+        # TYPE grpc_client_requests_sent_total counter
+		# HELP grpc_client_responses_received_total TODO
+        # TYPE grpc_client_responses_received_total counter
+	`
+	expected := `
+		grpc_client_requests_sent_total{grpc_client_user_agent="fake-user-agent",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",grpc_type="bidi_stream"} 1
+		grpc_client_responses_received_total{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service",grpc_type="bidi_stream"} 1
+	`
+
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_client_requests_sent_total", "grpc_client_responses_received_total"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,72 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/piotrkowalczuk/promgrpc/v4/internal/useragent"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+)
+
+// NewClientRetriedCallsTotalCounterVec allocates a new Prometheus CounterVec for the client and given set of options.
+func NewClientRetriedCallsTotalCounterVec(opts ...CollectorOption) *prometheus.CounterVec {
+	labels := []string{
+		labelCode,
+		labelIsFailFast,
+		labelMethod,
+		labelService,
+		labelClientUserAgent,
+	}
+	return newRetriedCallsTotalCounterVec("client", labels, opts...)
+}
+
+// ClientRetriedCallsTotalStatsHandler is responsible for counting calls that required more than one attempt, by the code the call finally ended with.
+// Compared against ClientResponsesTotalStatsHandler, it shows how many failures were masked by retries and how many retries did not help.
+// It requires StatsHandler.UnaryClientInterceptor and StatsHandler.StreamClientInterceptor to be installed.
+type ClientRetriedCallsTotalStatsHandler struct {
+	baseStatsHandler
+	uas useragent.Store
+	vec *prometheus.CounterVec
+}
+
+// NewClientRetriedCallsTotalStatsHandler ...
+func NewClientRetriedCallsTotalStatsHandler(vec *prometheus.CounterVec, opts ...StatsHandlerOption) *ClientRetriedCallsTotalStatsHandler {
+	h := &ClientRetriedCallsTotalStatsHandler{
+		vec: vec,
+	}
+	h.baseStatsHandler = baseStatsHandler{
+		collector: vec,
+		options: statsHandlerOptions{
+			handleRPCLabelFn: h.labels,
+		},
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ClientRetriedCallsTotalStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if pay, ok := stat.(*stats.OutHeader); ok {
+		_ = h.uas.ClientSide(ctx, pay)
+	}
+}
+
+func (h *ClientRetriedCallsTotalStatsHandler) handleCall(ctx context.Context, attempts int64, stat *stats.End) {
+	if attempts > 1 {
+		h.inc(ctx, h.vec.WithLabelValues(h.labelValues(ctx, stat)...))
+	}
+}
+
+func (h *ClientRetriedCallsTotalStatsHandler) labels(ctx context.Context, stat stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	return []string{
+		status.Code(stat.(*stats.End).Error).String(),
+		tag.isFailFast,
+		tag.method,
+		tag.service,
+		h.uas.ClientSide(ctx, stat),
+	}
+}
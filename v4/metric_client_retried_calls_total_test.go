@@ -0,0 +1,91 @@
+package promgrpc_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+)
+
+func TestNewClientRetriedCallsTotalStatsHandler(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.NewStatsHandler(promgrpc.NewClientRetriedCallsTotalStatsHandler(promgrpc.NewClientRetriedCallsTotalCounterVec()))
+	interceptor := h.StreamClientInterceptor()
+
+	streamer := func(attempts int, recvErr error) grpc.Streamer {
+		return func(ctx context.Context, _ *grpc.StreamDesc, _ *grpc.ClientConn, method string, _ ...grpc.CallOption) (grpc.ClientStream, error) {
+			for i := 0; i < attempts; i++ {
+				ctx := h.TagRPC(ctx, &stats.RPCTagInfo{
+					FullMethodName: method,
+					FailFast:       true,
+				})
+				h.HandleRPC(ctx, &stats.Begin{Client: true})
+				h.HandleRPC(ctx, &stats.OutHeader{
+					Client: true,
+					Header: metadata.MD{"user-agent": []string{"fake-user-agent"}},
+				})
+			}
+			return &fakeClientStream{recvErr: recvErr}, nil
+		}
+	}
+	desc := &grpc.StreamDesc{ServerStreams: true}
+
+	for _, given := range []struct {
+		attempts int
+		recvErr  error
+	}{
+		{attempts: 1, recvErr: io.EOF},
+		{attempts: 2, recvErr: io.EOF},
+		{attempts: 3, recvErr: status.Error(codes.Unavailable, "unavailable")},
+		{attempts: 2, recvErr: status.Error(codes.Unavailable, "unavailable")},
+	} {
+		cs, err := interceptor(ctx, desc, nil, "/service/Method", streamer(given.attempts, given.recvErr))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The end of a call must be reported only once.
+		for i := 0; i < 2; i++ {
+			if err := cs.RecvMsg(nil); err != given.recvErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+	}
+
+	const metadata = `
+		# HELP grpc_client_retried_calls_total Number of calls that required more than one attempt.
+        # TYPE grpc_client_retried_calls_total counter
+	`
+	expected := `
+		grpc_client_retried_calls_total{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service"} 1
+		grpc_client_retried_calls_total{grpc_client_user_agent="fake-user-agent",grpc_code="Unavailable",grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service"} 2
+	`
+
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_client_retried_calls_total"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type fakeClientStream struct {
+	grpc.ClientStream
+	recvErr error
+	sendErr error
+}
+
+func (s *fakeClientStream) SendMsg(_ any) error {
+	return s.sendErr
+}
+
+func (s *fakeClientStream) RecvMsg(_ any) error {
+	return s.recvErr
+}
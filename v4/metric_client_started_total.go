@@ -0,0 +1,63 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewClientStartedCounterVec allocates a new Prometheus CounterVec for the client and given set of options.
+// It is compatible with go-grpc-prometheus.
+func NewClientStartedCounterVec(opts ...CollectorOption) *prometheus.CounterVec {
+	labels := []string{
+		// keep alphabetical order
+		labelMethod,
+		labelService,
+		labelType,
+	}
+	return newStartedTotalCounterVec("client", "started_total", "Total number of RPCs started on the client.", labels, opts...)
+}
+
+// ClientStartedStatsHandler is responsible for counting outgoing RPCs as they begin, the same way go-grpc-prometheus does (grpc_client_started_total).
+// If a call is intercepted (see StatsHandler.UnaryClientInterceptor), it is counted once, when its first attempt begins.
+type ClientStartedStatsHandler struct {
+	baseStatsHandler
+	vec *prometheus.CounterVec
+}
+
+// NewClientStartedStatsHandler ...
+func NewClientStartedStatsHandler(vec *prometheus.CounterVec, opts ...StatsHandlerOption) *ClientStartedStatsHandler {
+	h := &ClientStartedStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+			options: statsHandlerOptions{
+				handleRPCLabelFn: clientStartedLabels,
+			},
+		},
+		vec: vec,
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ClientStartedStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if _, ok := stat.(*stats.Begin); ok && stat.IsClient() {
+		if ctx.Value(tagRPCKey).(*rpcTagLabels).attempt > 1 {
+			return
+		}
+		h.inc(ctx, h.vec.WithLabelValues(h.labelValues(ctx, stat)...))
+	}
+}
+
+func clientStartedLabels(ctx context.Context, _ stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	// keep alphabetical order
+	return []string{
+		tag.method,
+		tag.service,
+		tag.rpcType,
+	}
+}
@@ -0,0 +1,44 @@
+package promgrpc_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/stats"
+)
+
+func TestNewClientStartedStatsHandler(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.NewStatsHandler(promgrpc.NewClientStartedStatsHandler(promgrpc.NewClientStartedCounterVec()))
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+		FullMethodName: "/service/Method",
+		FailFast:       true,
+	})
+	h.HandleRPC(ctx, &stats.Begin{
+		Client:         true,
+		IsClientStream: true,
+		IsServerStream: true,
+	})
+	h.HandleRPC(ctx, &stats.Begin{
+		IsClientStream: true,
+		IsServerStream: true,
+	})
+
+	const metadata = `
+		# HELP grpc_client_started_total Total number of RPCs started on the client.
+        # TYPE grpc_client_started_total counter
+	`
+	expected := `
+		grpc_client_started_total{grpc_method="Method",grpc_service="service",grpc_type="bidi_stream"} 1
+	`
+
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_client_started_total"); err != nil {
+		t.Fatal(err)
+	}
+}
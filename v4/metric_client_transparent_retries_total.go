@@ -0,0 +1,58 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewClientTransparentRetriesTotalCounterVec allocates a new Prometheus CounterVec for the client and given set of options.
+func NewClientTransparentRetriesTotalCounterVec(opts ...CollectorOption) *prometheus.CounterVec {
+	labels := []string{
+		labelIsFailFast,
+		labelMethod,
+		labelService,
+	}
+	return newTransparentRetriesTotalCounterVec("client", labels, opts...)
+}
+
+// ClientTransparentRetriesTotalStatsHandler is responsible for counting attempts that gRPC retried transparently.
+// Transparent retries happen when an attempt never reached the server application, e.g. it was refused by a draining server.
+// They do not count against the retry policy of the service config.
+type ClientTransparentRetriesTotalStatsHandler struct {
+	baseStatsHandler
+	vec *prometheus.CounterVec
+}
+
+// NewClientTransparentRetriesTotalStatsHandler ...
+func NewClientTransparentRetriesTotalStatsHandler(vec *prometheus.CounterVec, opts ...StatsHandlerOption) *ClientTransparentRetriesTotalStatsHandler {
+	h := &ClientTransparentRetriesTotalStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+			options: statsHandlerOptions{
+				handleRPCLabelFn: clientTransparentRetriesTotalLabels,
+			},
+		},
+		vec: vec,
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ClientTransparentRetriesTotalStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if pay, ok := stat.(*stats.Begin); ok && stat.IsClient() && pay.IsTransparentRetryAttempt {
+		h.inc(ctx, h.vec.WithLabelValues(h.labelValues(ctx, stat)...))
+	}
+}
+
+func clientTransparentRetriesTotalLabels(ctx context.Context, _ stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	return []string{
+		tag.isFailFast,
+		tag.method,
+		tag.service,
+	}
+}
@@ -0,0 +1,49 @@
+package promgrpc_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/stats"
+)
+
+func TestNewClientTransparentRetriesTotalStatsHandler(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.NewStatsHandler(promgrpc.NewClientTransparentRetriesTotalStatsHandler(promgrpc.NewClientTransparentRetriesTotalCounterVec()))
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+		FullMethodName: "/service/Method",
+		FailFast:       true,
+	})
+	h.HandleRPC(ctx, &stats.Begin{
+		Client: true,
+	})
+	h.HandleRPC(ctx, &stats.Begin{
+		Client:                    true,
+		IsTransparentRetryAttempt: true,
+	})
+	h.HandleRPC(ctx, &stats.Begin{
+		Client:                    true,
+		IsTransparentRetryAttempt: true,
+	})
+	h.HandleRPC(ctx, &stats.Begin{
+		IsTransparentRetryAttempt: true,
+	})
+
+	const metadata = `
+		# HELP grpc_client_transparent_retries_total Number of attempts transparently retried by gRPC.
+        # TYPE grpc_client_transparent_retries_total counter
+	`
+	expected := `
+		grpc_client_transparent_retries_total{grpc_is_fail_fast="true",grpc_method="Method",grpc_service="service"} 2
+	`
+
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_client_transparent_retries_total"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,55 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewServerCallDurationHistogramVec allocates a new Prometheus HistogramVec for the server and given set of options.
+// It follows naming conventions of gRPC proposal A66.
+func NewServerCallDurationHistogramVec(opts ...CollectorOption) *prometheus.HistogramVec {
+	labels := []string{
+		labelMethod,
+		labelStatus,
+	}
+	return newLatencyHistogramVec("server", "call_duration_seconds", "End-to-end time taken to complete a call from server transport's perspective.", labels, opts...)
+}
+
+// ServerCallDurationStatsHandler is responsible for observing how long incoming calls take from the transport perspective, following gRPC proposal A66 (grpc.server.call.duration).
+type ServerCallDurationStatsHandler struct {
+	baseStatsHandler
+	vec prometheus.ObserverVec
+}
+
+// NewServerCallDurationStatsHandler ...
+func NewServerCallDurationStatsHandler(vec prometheus.ObserverVec, opts ...StatsHandlerOption) *ServerCallDurationStatsHandler {
+	h := &ServerCallDurationStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+			options: statsHandlerOptions{
+				handleRPCLabelFn: serverCallDurationLabels,
+			},
+		},
+		vec: vec,
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ServerCallDurationStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if pay, ok := stat.(*stats.End); ok && !stat.IsClient() {
+		h.observe(ctx, h.vec, h.labelValues(ctx, stat), pay.EndTime.Sub(pay.BeginTime).Seconds())
+	}
+}
+
+func serverCallDurationLabels(ctx context.Context, stat stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	return []string{
+		tag.a66Method(),
+		statusCodeName(stat.(*stats.End).Error),
+	}
+}
@@ -0,0 +1,77 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewServerCallRcvdTotalCompressedMessageSizeHistogramVec allocates a new Prometheus HistogramVec for the server and given set of options.
+// It follows naming conventions of gRPC proposal A66.
+func NewServerCallRcvdTotalCompressedMessageSizeHistogramVec(opts ...CollectorOption) *prometheus.HistogramVec {
+	labels := []string{
+		labelMethod,
+		labelStatus,
+	}
+	return newTotalCompressedMessageSizeHistogramVec("server", "call_rcvd_total_compressed_message_size_bytes", "Compressed message bytes received per server call.", labels, opts...)
+}
+
+// ServerCallRcvdTotalCompressedMessageSizeStatsHandler is responsible for observing the total size of compressed messages received within an incoming call,
+// following gRPC proposal A66 (grpc.server.call.rcvd_total_compressed_message_size).
+// Metadata and framing are not included.
+type ServerCallRcvdTotalCompressedMessageSizeStatsHandler struct {
+	baseStatsHandler
+	vec prometheus.ObserverVec
+}
+
+// NewServerCallRcvdTotalCompressedMessageSizeStatsHandler ...
+func NewServerCallRcvdTotalCompressedMessageSizeStatsHandler(vec prometheus.ObserverVec, opts ...StatsHandlerOption) *ServerCallRcvdTotalCompressedMessageSizeStatsHandler {
+	h := &ServerCallRcvdTotalCompressedMessageSizeStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+			options: statsHandlerOptions{
+				handleRPCLabelFn: serverCallRcvdTotalCompressedMessageSizeLabels,
+			},
+		},
+		vec: vec,
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// TagRPC implements stats Handler interface.
+func (h *ServerCallRcvdTotalCompressedMessageSizeStatsHandler) TagRPC(ctx context.Context, inf *stats.RPCTagInfo) context.Context {
+	ctx = h.baseStatsHandler.TagRPC(ctx, inf)
+	ctx = context.WithValue(ctx, serverCallRcvdTotalCompressedMessageSizeKey{}, &requestTotalMark{})
+	return ctx
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ServerCallRcvdTotalCompressedMessageSizeStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if stat.IsClient() {
+		return
+	}
+	mrk, ok := ctx.Value(serverCallRcvdTotalCompressedMessageSizeKey{}).(*requestTotalMark)
+	if !ok {
+		return
+	}
+
+	switch pay := stat.(type) {
+	case *stats.InPayload:
+		mrk.add(int64(pay.CompressedLength))
+	case *stats.End:
+		h.observe(ctx, h.vec, h.labelValues(ctx, stat), float64(mrk.load()))
+	}
+}
+
+func serverCallRcvdTotalCompressedMessageSizeLabels(ctx context.Context, stat stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	return []string{
+		tag.a66Method(),
+		statusCodeName(stat.(*stats.End).Error),
+	}
+}
+
+type serverCallRcvdTotalCompressedMessageSizeKey struct{}
@@ -0,0 +1,76 @@
+package promgrpc_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+)
+
+func TestNewServerCallRcvdTotalCompressedMessageSizeStatsHandler(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.NewStatsHandlerWithOptions(
+		[]promgrpc.StatsHandlerCollector{promgrpc.NewServerCallRcvdTotalCompressedMessageSizeStatsHandler(promgrpc.NewServerCallRcvdTotalCompressedMessageSizeHistogramVec())},
+		promgrpc.StatsHandlerWithRegisteredMethods("/package.Service/Method"),
+	)
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+		FullMethodName: "/package.Service/Method",
+		FailFast:       true,
+	})
+	h.HandleRPC(ctx, &stats.InPayload{
+		Length:           2000,
+		CompressedLength: 1000,
+	})
+	h.HandleRPC(ctx, &stats.InPayload{
+		Length:           2000,
+		CompressedLength: 1500,
+	})
+	h.HandleRPC(ctx, &stats.InPayload{
+		Client:           true,
+		Length:           2000,
+		CompressedLength: 1500,
+	})
+	h.HandleRPC(ctx, &stats.OutPayload{
+		Length:           2000,
+		CompressedLength: 1500,
+	})
+	h.HandleRPC(ctx, &stats.End{
+		Error: status.Error(codes.Unavailable, "unavailable"),
+	})
+
+	const metadata = `
+		# HELP grpc_server_call_rcvd_total_compressed_message_size_bytes Compressed message bytes received per server call.
+        # TYPE grpc_server_call_rcvd_total_compressed_message_size_bytes histogram
+	`
+	expected := `
+		grpc_server_call_rcvd_total_compressed_message_size_bytes_bucket{grpc_method="package.Service/Method",grpc_status="UNAVAILABLE",le="0"} 0
+        grpc_server_call_rcvd_total_compressed_message_size_bytes_bucket{grpc_method="package.Service/Method",grpc_status="UNAVAILABLE",le="1024"} 0
+        grpc_server_call_rcvd_total_compressed_message_size_bytes_bucket{grpc_method="package.Service/Method",grpc_status="UNAVAILABLE",le="2048"} 0
+        grpc_server_call_rcvd_total_compressed_message_size_bytes_bucket{grpc_method="package.Service/Method",grpc_status="UNAVAILABLE",le="4096"} 1
+        grpc_server_call_rcvd_total_compressed_message_size_bytes_bucket{grpc_method="package.Service/Method",grpc_status="UNAVAILABLE",le="16384"} 1
+        grpc_server_call_rcvd_total_compressed_message_size_bytes_bucket{grpc_method="package.Service/Method",grpc_status="UNAVAILABLE",le="65536"} 1
+        grpc_server_call_rcvd_total_compressed_message_size_bytes_bucket{grpc_method="package.Service/Method",grpc_status="UNAVAILABLE",le="262144"} 1
+        grpc_server_call_rcvd_total_compressed_message_size_bytes_bucket{grpc_method="package.Service/Method",grpc_status="UNAVAILABLE",le="1048576"} 1
+        grpc_server_call_rcvd_total_compressed_message_size_bytes_bucket{grpc_method="package.Service/Method",grpc_status="UNAVAILABLE",le="4194304"} 1
+        grpc_server_call_rcvd_total_compressed_message_size_bytes_bucket{grpc_method="package.Service/Method",grpc_status="UNAVAILABLE",le="16777216"} 1
+        grpc_server_call_rcvd_total_compressed_message_size_bytes_bucket{grpc_method="package.Service/Method",grpc_status="UNAVAILABLE",le="67108864"} 1
+        grpc_server_call_rcvd_total_compressed_message_size_bytes_bucket{grpc_method="package.Service/Method",grpc_status="UNAVAILABLE",le="268435456"} 1
+        grpc_server_call_rcvd_total_compressed_message_size_bytes_bucket{grpc_method="package.Service/Method",grpc_status="UNAVAILABLE",le="1073741824"} 1
+        grpc_server_call_rcvd_total_compressed_message_size_bytes_bucket{grpc_method="package.Service/Method",grpc_status="UNAVAILABLE",le="4294967296"} 1
+        grpc_server_call_rcvd_total_compressed_message_size_bytes_bucket{grpc_method="package.Service/Method",grpc_status="UNAVAILABLE",le="+Inf"} 1
+        grpc_server_call_rcvd_total_compressed_message_size_bytes_sum{grpc_method="package.Service/Method",grpc_status="UNAVAILABLE"} 2500
+        grpc_server_call_rcvd_total_compressed_message_size_bytes_count{grpc_method="package.Service/Method",grpc_status="UNAVAILABLE"} 1
+	`
+
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_server_call_rcvd_total_compressed_message_size_bytes"); err != nil {
+		t.Fatal(err)
+	}
+}
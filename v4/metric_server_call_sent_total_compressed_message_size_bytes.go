@@ -0,0 +1,77 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewServerCallSentTotalCompressedMessageSizeHistogramVec allocates a new Prometheus HistogramVec for the server and given set of options.
+// It follows naming conventions of gRPC proposal A66.
+func NewServerCallSentTotalCompressedMessageSizeHistogramVec(opts ...CollectorOption) *prometheus.HistogramVec {
+	labels := []string{
+		labelMethod,
+		labelStatus,
+	}
+	return newTotalCompressedMessageSizeHistogramVec("server", "call_sent_total_compressed_message_size_bytes", "Compressed message bytes sent per server call.", labels, opts...)
+}
+
+// ServerCallSentTotalCompressedMessageSizeStatsHandler is responsible for observing the total size of compressed messages sent within an incoming call,
+// following gRPC proposal A66 (grpc.server.call.sent_total_compressed_message_size).
+// Metadata and framing are not included.
+type ServerCallSentTotalCompressedMessageSizeStatsHandler struct {
+	baseStatsHandler
+	vec prometheus.ObserverVec
+}
+
+// NewServerCallSentTotalCompressedMessageSizeStatsHandler ...
+func NewServerCallSentTotalCompressedMessageSizeStatsHandler(vec prometheus.ObserverVec, opts ...StatsHandlerOption) *ServerCallSentTotalCompressedMessageSizeStatsHandler {
+	h := &ServerCallSentTotalCompressedMessageSizeStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+			options: statsHandlerOptions{
+				handleRPCLabelFn: serverCallSentTotalCompressedMessageSizeLabels,
+			},
+		},
+		vec: vec,
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// TagRPC implements stats Handler interface.
+func (h *ServerCallSentTotalCompressedMessageSizeStatsHandler) TagRPC(ctx context.Context, inf *stats.RPCTagInfo) context.Context {
+	ctx = h.baseStatsHandler.TagRPC(ctx, inf)
+	ctx = context.WithValue(ctx, serverCallSentTotalCompressedMessageSizeKey{}, &requestTotalMark{})
+	return ctx
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ServerCallSentTotalCompressedMessageSizeStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if stat.IsClient() {
+		return
+	}
+	mrk, ok := ctx.Value(serverCallSentTotalCompressedMessageSizeKey{}).(*requestTotalMark)
+	if !ok {
+		return
+	}
+
+	switch pay := stat.(type) {
+	case *stats.OutPayload:
+		mrk.add(int64(pay.CompressedLength))
+	case *stats.End:
+		h.observe(ctx, h.vec, h.labelValues(ctx, stat), float64(mrk.load()))
+	}
+}
+
+func serverCallSentTotalCompressedMessageSizeLabels(ctx context.Context, stat stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	return []string{
+		tag.a66Method(),
+		statusCodeName(stat.(*stats.End).Error),
+	}
+}
+
+type serverCallSentTotalCompressedMessageSizeKey struct{}
@@ -0,0 +1,60 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewServerCallStartedCounterVec allocates a new Prometheus CounterVec for the server and given set of options.
+// It follows naming conventions of gRPC proposal A66.
+func NewServerCallStartedCounterVec(opts ...CollectorOption) *prometheus.CounterVec {
+	labels := []string{
+		labelMethod,
+	}
+	return newStartedTotalCounterVec("server", "call_started_total", "Number of server calls started.", labels, opts...)
+}
+
+// ServerCallStartedStatsHandler is responsible for counting incoming calls as they begin, following gRPC proposal A66 (grpc.server.call.started).
+type ServerCallStartedStatsHandler struct {
+	baseStatsHandler
+	vec *prometheus.CounterVec
+}
+
+// NewServerCallStartedStatsHandler ...
+func NewServerCallStartedStatsHandler(vec *prometheus.CounterVec, opts ...StatsHandlerOption) *ServerCallStartedStatsHandler {
+	h := &ServerCallStartedStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+			options: statsHandlerOptions{
+				handleRPCLabelFn: serverCallStartedLabels,
+			},
+		},
+		vec: vec,
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ServerCallStartedStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if _, ok := stat.(*stats.Begin); ok && !stat.IsClient() {
+		h.inc(ctx, h.vec.WithLabelValues(h.labelValues(ctx, stat)...))
+	}
+}
+
+// Preinitialize creates series of every method a server exposes, see StatsHandler.Preinitialize.
+func (h *ServerCallStartedStatsHandler) Preinitialize(provider ServiceInfoProvider) {
+	for _, lvs := range h.preinitializedLabels(provider, &stats.Begin{}) {
+		h.vec.WithLabelValues(lvs...)
+	}
+}
+
+func serverCallStartedLabels(ctx context.Context, _ stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	return []string{
+		tag.a66Method(),
+	}
+}
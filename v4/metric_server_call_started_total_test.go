@@ -0,0 +1,49 @@
+package promgrpc_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/stats"
+)
+
+func TestNewServerCallStartedStatsHandler(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.NewStatsHandlerWithOptions(
+		[]promgrpc.StatsHandlerCollector{promgrpc.NewServerCallStartedStatsHandler(promgrpc.NewServerCallStartedCounterVec())},
+		promgrpc.StatsHandlerWithRegisteredMethods("/package.Service/Method"),
+	)
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+		FullMethodName: "/package.Service/Method",
+		FailFast:       true,
+	})
+	h.HandleRPC(ctx, &stats.Begin{})
+	h.HandleRPC(ctx, &stats.Begin{
+		Client: true,
+	})
+
+	// Methods not registered on the server are reported as other.
+	for _, method := range []string{"/package.Service/Unknown", "/random.Service/Method"} {
+		ctx := h.TagRPC(ctx, &stats.RPCTagInfo{FullMethodName: method})
+		h.HandleRPC(ctx, &stats.Begin{})
+	}
+
+	const metadata = `
+		# HELP grpc_server_call_started_total Number of server calls started.
+        # TYPE grpc_server_call_started_total counter
+	`
+	expected := `
+		grpc_server_call_started_total{grpc_method="other"} 2
+		grpc_server_call_started_total{grpc_method="package.Service/Method"} 1
+	`
+
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_server_call_started_total"); err != nil {
+		t.Fatal(err)
+	}
+}
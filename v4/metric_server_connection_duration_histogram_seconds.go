@@ -0,0 +1,95 @@
+package promgrpc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewServerConnectionDurationHistogramVec allocates a new Prometheus HistogramVec for the server and given set of options.
+func NewServerConnectionDurationHistogramVec(opts ...CollectorOption) *prometheus.HistogramVec {
+	labels := []string{labelLocalAddr, labelClientUserAgent}
+	return newConnectionDurationHistogramVec("server", labels, opts...)
+}
+
+// ServerConnectionDurationStatsHandler is responsible for observing how long incoming connections stay open, from stats.ConnBegin until stats.ConnEnd.
+// Short-lived connections usually point at a load balancer idle timeout or at a peer that opens a connection per call.
+type ServerConnectionDurationStatsHandler struct {
+	baseStatsHandler
+	vec prometheus.ObserverVec
+}
+
+// NewServerConnectionDurationStatsHandler ...
+func NewServerConnectionDurationStatsHandler(vec prometheus.ObserverVec) *ServerConnectionDurationStatsHandler {
+	return &ServerConnectionDurationStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+		},
+		vec: vec,
+	}
+}
+
+// TagConn implements stats Handler interface.
+func (h *ServerConnectionDurationStatsHandler) TagConn(ctx context.Context, inf *stats.ConnTagInfo) context.Context {
+	ctx = h.baseStatsHandler.TagConn(ctx, inf)
+	ctx = context.WithValue(ctx, serverConnectionDurationKey{}, &connectionMark{})
+	return ctx
+}
+
+// HandleConn implements stats Handler interface.
+func (h *ServerConnectionDurationStatsHandler) HandleConn(ctx context.Context, stat stats.ConnStats) {
+	if stat.IsClient() {
+		return
+	}
+	mrk, ok := ctx.Value(serverConnectionDurationKey{}).(*connectionMark)
+	if !ok {
+		return
+	}
+
+	switch stat.(type) {
+	case *stats.ConnBegin:
+		mrk.begin(time.Now())
+	case *stats.ConnEnd:
+		if elapsed, ok := mrk.end(time.Now()); ok {
+			h.vec.WithLabelValues(h.labels(ctx)...).Observe(elapsed.Seconds())
+		}
+	}
+}
+
+func (h *ServerConnectionDurationStatsHandler) labels(ctx context.Context) []string {
+	tag := ctx.Value(tagConnKey).(connTagLabels)
+	return []string{
+		tag.localAddr,
+		tag.clientUserAgent,
+	}
+}
+
+type serverConnectionDurationKey struct{}
+
+// connectionMark keeps track of when a connection began.
+// stats.ConnBegin and stats.ConnEnd can be reported by different goroutines, hence the lock.
+type connectionMark struct {
+	lock  sync.Mutex
+	start time.Time
+}
+
+func (m *connectionMark) begin(t time.Time) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.start = t
+}
+
+// end returns time elapsed since the beginning of a connection, but only if the beginning was reported.
+func (m *connectionMark) end(t time.Time) (time.Duration, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.start.IsZero() {
+		return 0, false
+	}
+	return t.Sub(m.start), true
+}
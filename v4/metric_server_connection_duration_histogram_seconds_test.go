@@ -0,0 +1,53 @@
+package promgrpc_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/piotrkowalczuk/promgrpc/v4/internal/testutil"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+)
+
+func TestNewServerConnectionDurationStatsHandler(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.NewServerConnectionDurationStatsHandler(promgrpc.NewServerConnectionDurationHistogramVec())
+	ctx = metadata.NewIncomingContext(ctx, metadata.MD{"user-agent": []string{"fake-user-agent"}})
+	ctx = h.TagConn(ctx, &stats.ConnTagInfo{
+		LocalAddr: &net.TCPAddr{
+			IP:   net.IPv4(1, 2, 3, 4),
+			Port: 80,
+		},
+		RemoteAddr: &net.TCPAddr{
+			IP:   net.IPv4(4, 3, 2, 1),
+			Port: 111,
+		},
+	})
+	h.HandleConn(ctx, &stats.ConnBegin{})
+	time.Sleep(10 * time.Millisecond)
+	h.HandleConn(ctx, &stats.ConnEnd{Client: true})
+	h.HandleConn(ctx, &stats.ConnEnd{})
+
+	reg := prometheus.NewRegistry()
+	registerCollector(t, reg, h)
+
+	testutil.AssertMetricValue(t, reg, "grpc_server_connection_duration_histogram_seconds_count", 1)
+	testutil.AssertMetricDimensions(t, reg, "grpc_server_connection_duration_histogram_seconds_count", map[string]string{
+		"grpc_client_user_agent": "fake-user-agent",
+		"grpc_local_addr":        "1.2.3.4:80",
+	})
+
+	mf, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := mf[0].GetMetric()[0].GetHistogram().GetSampleSum(); got < 0.01 {
+		t.Errorf("connection duration is too short, expected at least 0.01 but got %g", got)
+	}
+}
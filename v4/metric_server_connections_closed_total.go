@@ -0,0 +1,46 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewServerConnectionsClosedTotalCounterVec allocates a new Prometheus CounterVec for the server and given set of options.
+func NewServerConnectionsClosedTotalCounterVec(opts ...CollectorOption) *prometheus.CounterVec {
+	labels := []string{labelLocalAddr, labelClientUserAgent}
+	return newConnectionsClosedTotalCounterVec("server", labels, opts...)
+}
+
+// ServerConnectionsClosedTotalStatsHandler is responsible for counting incoming connections once they are closed.
+// Unlike ServerConnectionsStatsHandler, it makes connection churn visible, e.g. connections dropped by a load balancer idle timeout.
+type ServerConnectionsClosedTotalStatsHandler struct {
+	baseStatsHandler
+	vec *prometheus.CounterVec
+}
+
+// NewServerConnectionsClosedTotalStatsHandler ...
+func NewServerConnectionsClosedTotalStatsHandler(vec *prometheus.CounterVec) *ServerConnectionsClosedTotalStatsHandler {
+	return &ServerConnectionsClosedTotalStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+		},
+		vec: vec,
+	}
+}
+
+// HandleConn implements stats Handler interface.
+func (h *ServerConnectionsClosedTotalStatsHandler) HandleConn(ctx context.Context, stat stats.ConnStats) {
+	if _, ok := stat.(*stats.ConnEnd); ok && !stat.IsClient() {
+		h.vec.WithLabelValues(h.labels(ctx)...).Inc()
+	}
+}
+
+func (h *ServerConnectionsClosedTotalStatsHandler) labels(ctx context.Context) []string {
+	tag := ctx.Value(tagConnKey).(connTagLabels)
+	return []string{
+		tag.localAddr,
+		tag.clientUserAgent,
+	}
+}
@@ -0,0 +1,46 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewServerConnectionsOpenedTotalCounterVec allocates a new Prometheus CounterVec for the server and given set of options.
+func NewServerConnectionsOpenedTotalCounterVec(opts ...CollectorOption) *prometheus.CounterVec {
+	labels := []string{labelLocalAddr, labelClientUserAgent}
+	return newConnectionsOpenedTotalCounterVec("server", labels, opts...)
+}
+
+// ServerConnectionsOpenedTotalStatsHandler is responsible for counting incoming connections once they are established.
+// Unlike ServerConnectionsStatsHandler, it makes connection churn visible, e.g. connection storms after a deployment.
+type ServerConnectionsOpenedTotalStatsHandler struct {
+	baseStatsHandler
+	vec *prometheus.CounterVec
+}
+
+// NewServerConnectionsOpenedTotalStatsHandler ...
+func NewServerConnectionsOpenedTotalStatsHandler(vec *prometheus.CounterVec) *ServerConnectionsOpenedTotalStatsHandler {
+	return &ServerConnectionsOpenedTotalStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+		},
+		vec: vec,
+	}
+}
+
+// HandleConn implements stats Handler interface.
+func (h *ServerConnectionsOpenedTotalStatsHandler) HandleConn(ctx context.Context, stat stats.ConnStats) {
+	if _, ok := stat.(*stats.ConnBegin); ok && !stat.IsClient() {
+		h.vec.WithLabelValues(h.labels(ctx)...).Inc()
+	}
+}
+
+func (h *ServerConnectionsOpenedTotalStatsHandler) labels(ctx context.Context) []string {
+	tag := ctx.Value(tagConnKey).(connTagLabels)
+	return []string{
+		tag.localAddr,
+		tag.clientUserAgent,
+	}
+}
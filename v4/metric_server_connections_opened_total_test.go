@@ -0,0 +1,57 @@
+package promgrpc_test
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+)
+
+func TestNewServerConnectionsOpenedTotalStatsHandler(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.NewServerConnectionsOpenedTotalStatsHandler(promgrpc.NewServerConnectionsOpenedTotalCounterVec())
+	ctx = metadata.NewIncomingContext(ctx, metadata.MD{"user-agent": []string{"fake-user-agent"}})
+	ctx1 := h.TagConn(ctx, &stats.ConnTagInfo{
+		LocalAddr: &net.TCPAddr{
+			IP:   net.IPv4(1, 2, 3, 4),
+			Port: 80,
+		},
+		RemoteAddr: &net.TCPAddr{
+			IP:   net.IPv4(4, 3, 2, 1),
+			Port: 111,
+		},
+	})
+	h.HandleConn(ctx1, &stats.ConnBegin{})
+	h.HandleConn(ctx1, &stats.ConnBegin{Client: true})
+	ctx2 := h.TagConn(ctx, &stats.ConnTagInfo{
+		LocalAddr: &net.TCPAddr{
+			IP:   net.IPv4(1, 2, 3, 4),
+			Port: 90,
+		},
+		RemoteAddr: &net.TCPAddr{
+			IP:   net.IPv4(4, 3, 2, 1),
+			Port: 111,
+		},
+	})
+	h.HandleConn(ctx2, &stats.ConnBegin{})
+
+	const metadata = `
+		# HELP grpc_server_connections_opened_total Number of connections opened.
+		# TYPE grpc_server_connections_opened_total counter
+	`
+	expected := `
+		grpc_server_connections_opened_total{grpc_client_user_agent="fake-user-agent",grpc_local_addr="1.2.3.4:80"} 1
+        grpc_server_connections_opened_total{grpc_client_user_agent="fake-user-agent",grpc_local_addr="1.2.3.4:90"} 1
+	`
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_server_connections_opened_total"); err != nil {
+		t.Fatal(err)
+	}
+}
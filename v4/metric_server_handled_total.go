@@ -0,0 +1,69 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+)
+
+// NewServerHandledCounterVec allocates a new Prometheus CounterVec for the server and given set of options.
+// It is compatible with go-grpc-prometheus.
+func NewServerHandledCounterVec(opts ...CollectorOption) *prometheus.CounterVec {
+	labels := []string{
+		// keep alphabetical order
+		labelCode,
+		labelMethod,
+		labelService,
+		labelType,
+	}
+	return newHandledTotalCounterVec("server", "Total number of RPCs completed on the server, regardless of success or failure.", labels, opts...)
+}
+
+// ServerHandledStatsHandler is responsible for counting incoming RPCs once they complete, regardless of success or failure, the same way go-grpc-prometheus does (grpc_server_handled_total).
+type ServerHandledStatsHandler struct {
+	baseStatsHandler
+	vec *prometheus.CounterVec
+}
+
+// NewServerHandledStatsHandler ...
+func NewServerHandledStatsHandler(vec *prometheus.CounterVec, opts ...StatsHandlerOption) *ServerHandledStatsHandler {
+	h := &ServerHandledStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+			options: statsHandlerOptions{
+				handleRPCLabelFn: serverHandledLabels,
+			},
+		},
+		vec: vec,
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ServerHandledStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if _, ok := stat.(*stats.End); ok && !stat.IsClient() {
+		h.inc(ctx, h.vec.WithLabelValues(h.labelValues(ctx, stat)...))
+	}
+}
+
+// Preinitialize creates series of every method a server exposes, see StatsHandler.Preinitialize.
+func (h *ServerHandledStatsHandler) Preinitialize(provider ServiceInfoProvider) {
+	for _, lvs := range h.preinitializedLabels(provider, everyCodeEnd()...) {
+		h.vec.WithLabelValues(lvs...)
+	}
+}
+
+func serverHandledLabels(ctx context.Context, stat stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	// keep alphabetical order
+	return []string{
+		status.Code(stat.(*stats.End).Error).String(),
+		tag.method,
+		tag.service,
+		tag.rpcType,
+	}
+}
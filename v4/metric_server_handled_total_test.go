@@ -0,0 +1,68 @@
+package promgrpc_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/piotrkowalczuk/promgrpc/v4/pb/private/test"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+)
+
+func TestNewServerHandledStatsHandler(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.NewStatsHandler(promgrpc.NewServerHandledStatsHandler(promgrpc.NewServerHandledCounterVec()))
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+		FullMethodName: "/service/Method",
+		FailFast:       true,
+	})
+	begin := time.Now()
+	h.HandleRPC(ctx, &stats.Begin{
+		BeginTime:      begin,
+		IsClientStream: true,
+		IsServerStream: true,
+	})
+	h.HandleRPC(ctx, &stats.End{
+		BeginTime: begin,
+		EndTime:   begin.Add(100 * time.Millisecond),
+		Error:     status.Error(codes.Unavailable, "unavailable"),
+	})
+	h.HandleRPC(ctx, &stats.End{
+		Client:    true,
+		BeginTime: begin,
+		EndTime:   begin.Add(100 * time.Millisecond),
+	})
+
+	const metadata = `
+		# HELP grpc_server_handled_total Total number of RPCs completed on the server, regardless of success or failure.
+        # TYPE grpc_server_handled_total counter
+	`
+	expected := `
+		grpc_server_handled_total{grpc_code="Unavailable",grpc_method="Method",grpc_service="service",grpc_type="bidi_stream"} 1
+	`
+
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_server_handled_total"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestServerHandledStatsHandler_Preinitialize(t *testing.T) {
+	srv := grpc.NewServer()
+	test.RegisterTestServiceServer(srv, newDemoServer())
+
+	h := promgrpc.NewServerHandledStatsHandler(promgrpc.NewServerHandledCounterVec())
+	h.Preinitialize(srv)
+
+	// 4 methods times 17 status codes.
+	if n := testutil.CollectAndCount(h, "grpc_server_handled_total"); n != 68 {
+		t.Fatalf("unexpected number of series, expected 68 but got %d", n)
+	}
+}
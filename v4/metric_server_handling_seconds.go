@@ -0,0 +1,62 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+)
+
+// NewServerHandlingSecondsHistogramVec allocates a new Prometheus HistogramVec for the server and given set of options.
+// It is compatible with go-grpc-prometheus.
+func NewServerHandlingSecondsHistogramVec(opts ...CollectorOption) *prometheus.HistogramVec {
+	labels := []string{
+		// keep alphabetical order
+		labelCode,
+		labelMethod,
+		labelService,
+		labelType,
+	}
+	return newHandlingSecondsHistogramVec("server", "Histogram of response latency (seconds) of gRPC that had been application-level handled by the server.", labels, opts...)
+}
+
+// ServerHandlingSecondsStatsHandler is responsible for observing how long incoming RPCs take, the same way go-grpc-prometheus does (grpc_server_handling_seconds).
+type ServerHandlingSecondsStatsHandler struct {
+	baseStatsHandler
+	vec prometheus.ObserverVec
+}
+
+// NewServerHandlingSecondsStatsHandler ...
+func NewServerHandlingSecondsStatsHandler(vec prometheus.ObserverVec, opts ...StatsHandlerOption) *ServerHandlingSecondsStatsHandler {
+	h := &ServerHandlingSecondsStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+			options: statsHandlerOptions{
+				handleRPCLabelFn: serverHandlingSecondsLabels,
+			},
+		},
+		vec: vec,
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ServerHandlingSecondsStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if pay, ok := stat.(*stats.End); ok && !stat.IsClient() {
+		h.observe(ctx, h.vec, h.labelValues(ctx, stat), pay.EndTime.Sub(pay.BeginTime).Seconds())
+	}
+}
+
+func serverHandlingSecondsLabels(ctx context.Context, stat stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	// keep alphabetical order
+	return []string{
+		status.Code(stat.(*stats.End).Error).String(),
+		tag.method,
+		tag.service,
+		tag.rpcType,
+	}
+}
@@ -0,0 +1,157 @@
+package promgrpc
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewServerIdleConnectionsGaugeVec allocates a new Prometheus GaugeVec for the server and given set of options.
+func NewServerIdleConnectionsGaugeVec(opts ...CollectorOption) *prometheus.GaugeVec {
+	labels := []string{labelLocalAddr, labelClientUserAgent}
+	return newIdleConnectionsGaugeVec("server", labels, opts...)
+}
+
+// ServerIdleConnectionsStatsHandler is responsible for reporting the number of open incoming connections that carry no RPCs at the moment.
+// On the server side, grpc-go derives the context of an RPC from the context of its connection.
+// That is how RPCs are attributed to connections.
+// The gauge is computed during collection.
+type ServerIdleConnectionsStatsHandler struct {
+	baseStatsHandler
+	vec   *prometheus.GaugeVec
+	lock  sync.Mutex
+	conns connectionRegistry
+}
+
+// NewServerIdleConnectionsStatsHandler ...
+func NewServerIdleConnectionsStatsHandler(vec *prometheus.GaugeVec) *ServerIdleConnectionsStatsHandler {
+	return &ServerIdleConnectionsStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+		},
+		vec: vec,
+	}
+}
+
+// TagConn implements stats Handler interface.
+func (h *ServerIdleConnectionsStatsHandler) TagConn(ctx context.Context, inf *stats.ConnTagInfo) context.Context {
+	ctx = h.baseStatsHandler.TagConn(ctx, inf)
+	ctx = context.WithValue(ctx, serverIdleConnectionsKey{}, &connectionRequests{labels: h.labels(ctx)})
+	return ctx
+}
+
+// HandleConn implements stats Handler interface.
+func (h *ServerIdleConnectionsStatsHandler) HandleConn(ctx context.Context, stat stats.ConnStats) {
+	if stat.IsClient() {
+		return
+	}
+	conn, ok := ctx.Value(serverIdleConnectionsKey{}).(*connectionRequests)
+	if !ok {
+		return
+	}
+
+	switch stat.(type) {
+	case *stats.ConnBegin:
+		h.conns.add(conn)
+	case *stats.ConnEnd:
+		h.conns.remove(conn)
+	}
+}
+
+func (h *ServerIdleConnectionsStatsHandler) tracksConnections() {}
+
+// HandleRPC implements stats Handler interface.
+func (h *ServerIdleConnectionsStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if stat.IsClient() {
+		return
+	}
+	conn, ok := ctx.Value(serverIdleConnectionsKey{}).(*connectionRequests)
+	if !ok {
+		return
+	}
+
+	switch stat.(type) {
+	case *stats.Begin:
+		conn.begin()
+	case *stats.End:
+		conn.end()
+	}
+}
+
+// Collect implements prometheus Collector interface.
+func (h *ServerIdleConnectionsStatsHandler) Collect(in chan<- prometheus.Metric) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.vec.Reset()
+	h.conns.each(func(conn *connectionRequests) {
+		if conn.active.Load() == 0 {
+			h.vec.WithLabelValues(conn.labels...).Inc()
+		}
+	})
+	h.vec.Collect(in)
+}
+
+func (h *ServerIdleConnectionsStatsHandler) labels(ctx context.Context) []string {
+	tag := ctx.Value(tagConnKey).(connTagLabels)
+	return []string{
+		tag.localAddr,
+		tag.clientUserAgent,
+	}
+}
+
+type serverIdleConnectionsKey struct{}
+
+// connectionRequests keeps track of RPCs carried by a single connection.
+type connectionRequests struct {
+	labels []string
+	// active is the number of RPCs in progress.
+	active atomic.Int64
+	// total is the number of RPCs carried so far.
+	total atomic.Int64
+}
+
+// begin returns the number of RPCs in progress, including the one that begins.
+func (c *connectionRequests) begin() int64 {
+	c.total.Add(1)
+	return c.active.Add(1)
+}
+
+func (c *connectionRequests) end() {
+	c.active.Add(-1)
+}
+
+// connectionRegistry keeps track of open connections.
+type connectionRegistry struct {
+	lock  sync.Mutex
+	conns map[*connectionRequests]struct{}
+}
+
+func (r *connectionRegistry) add(conn *connectionRequests) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.conns == nil {
+		r.conns = make(map[*connectionRequests]struct{})
+	}
+	r.conns[conn] = struct{}{}
+}
+
+func (r *connectionRegistry) remove(conn *connectionRequests) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	delete(r.conns, conn)
+}
+
+func (r *connectionRegistry) each(fn func(*connectionRequests)) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for conn := range r.conns {
+		fn(conn)
+	}
+}
@@ -0,0 +1,82 @@
+package promgrpc_test
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+)
+
+func TestNewServerIdleConnectionsStatsHandler(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.NewStatsHandler(promgrpc.NewServerIdleConnectionsStatsHandler(promgrpc.NewServerIdleConnectionsGaugeVec()))
+	ctx = metadata.NewIncomingContext(ctx, metadata.MD{"user-agent": []string{"fake-user-agent"}})
+	ctx1 := h.TagConn(ctx, &stats.ConnTagInfo{
+		LocalAddr: &net.TCPAddr{
+			IP:   net.IPv4(1, 2, 3, 4),
+			Port: 80,
+		},
+		RemoteAddr: &net.TCPAddr{
+			IP:   net.IPv4(4, 3, 2, 1),
+			Port: 111,
+		},
+	})
+	h.HandleConn(ctx1, &stats.ConnBegin{})
+	ctx2 := h.TagConn(ctx, &stats.ConnTagInfo{
+		LocalAddr: &net.TCPAddr{
+			IP:   net.IPv4(1, 2, 3, 4),
+			Port: 90,
+		},
+		RemoteAddr: &net.TCPAddr{
+			IP:   net.IPv4(4, 3, 2, 1),
+			Port: 111,
+		},
+	})
+	h.HandleConn(ctx2, &stats.ConnBegin{})
+	ctx3 := h.TagConn(ctx, &stats.ConnTagInfo{
+		LocalAddr: &net.TCPAddr{
+			IP:   net.IPv4(1, 2, 3, 4),
+			Port: 100,
+		},
+		RemoteAddr: &net.TCPAddr{
+			IP:   net.IPv4(4, 3, 2, 1),
+			Port: 111,
+		},
+	})
+	h.HandleConn(ctx3, &stats.ConnBegin{})
+	h.HandleConn(ctx3, &stats.ConnEnd{})
+
+	rpcCtx := h.TagRPC(ctx1, &stats.RPCTagInfo{
+		FullMethodName: "/service/Method",
+	})
+	h.HandleRPC(rpcCtx, &stats.Begin{})
+
+	const metadata = `
+		# HELP grpc_server_idle_connections Number of open connections that carry no RPCs.
+		# TYPE grpc_server_idle_connections gauge
+	`
+	expected := `
+		grpc_server_idle_connections{grpc_client_user_agent="fake-user-agent",grpc_local_addr="1.2.3.4:90"} 1
+	`
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_server_idle_connections"); err != nil {
+		t.Fatal(err)
+	}
+
+	h.HandleRPC(rpcCtx, &stats.End{})
+
+	expected = `
+		grpc_server_idle_connections{grpc_client_user_agent="fake-user-agent",grpc_local_addr="1.2.3.4:80"} 1
+		grpc_server_idle_connections{grpc_client_user_agent="fake-user-agent",grpc_local_addr="1.2.3.4:90"} 1
+	`
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_server_idle_connections"); err != nil {
+		t.Fatal(err)
+	}
+}
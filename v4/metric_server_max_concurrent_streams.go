@@ -0,0 +1,114 @@
+package promgrpc
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewServerMaxConcurrentStreamsGaugeVec allocates a new Prometheus GaugeVec for the server and given set of options.
+func NewServerMaxConcurrentStreamsGaugeVec(opts ...CollectorOption) *prometheus.GaugeVec {
+	labels := []string{labelSource}
+	return newMaxConcurrentStreamsGaugeVec("server", labels, opts...)
+}
+
+// ServerMaxConcurrentStreamsStatsHandler is responsible for reporting the highest number of RPCs concurrently active on any single incoming connection.
+// The number at the time of a collection is reported with grpc_source="observed" label.
+// The highest number since the start, that does not miss short spikes between scrapes, is reported with grpc_source="peak" label.
+// Neither depends on how often, or by how many scrapers, the stats handler is collected.
+// If a limit is given, it is reported next to them with grpc_source="configured" label.
+// The limit is expected to be the same value as passed to grpc.MaxConcurrentStreams server option.
+type ServerMaxConcurrentStreamsStatsHandler struct {
+	baseStatsHandler
+	vec   *prometheus.GaugeVec
+	lock  sync.Mutex
+	conns connectionRegistry
+	// peak is a high-water mark of RPCs concurrently active on a single connection.
+	peak atomic.Int64
+}
+
+// NewServerMaxConcurrentStreamsStatsHandler ...
+// A limit equal to zero means that it is not known and will not be reported.
+func NewServerMaxConcurrentStreamsStatsHandler(vec *prometheus.GaugeVec, limit uint32) *ServerMaxConcurrentStreamsStatsHandler {
+	if limit > 0 {
+		vec.WithLabelValues(sourceConfigured).Set(float64(limit))
+	}
+	return &ServerMaxConcurrentStreamsStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+		},
+		vec: vec,
+	}
+}
+
+// TagConn implements stats Handler interface.
+func (h *ServerMaxConcurrentStreamsStatsHandler) TagConn(ctx context.Context, inf *stats.ConnTagInfo) context.Context {
+	ctx = h.baseStatsHandler.TagConn(ctx, inf)
+	ctx = context.WithValue(ctx, serverMaxConcurrentStreamsKey{}, &connectionRequests{})
+	return ctx
+}
+
+// HandleConn implements stats Handler interface.
+func (h *ServerMaxConcurrentStreamsStatsHandler) HandleConn(ctx context.Context, stat stats.ConnStats) {
+	if stat.IsClient() {
+		return
+	}
+	conn, ok := ctx.Value(serverMaxConcurrentStreamsKey{}).(*connectionRequests)
+	if !ok {
+		return
+	}
+
+	switch stat.(type) {
+	case *stats.ConnBegin:
+		h.conns.add(conn)
+	case *stats.ConnEnd:
+		h.conns.remove(conn)
+	}
+}
+
+func (h *ServerMaxConcurrentStreamsStatsHandler) tracksConnections() {}
+
+// HandleRPC implements stats Handler interface.
+func (h *ServerMaxConcurrentStreamsStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if stat.IsClient() {
+		return
+	}
+	conn, ok := ctx.Value(serverMaxConcurrentStreamsKey{}).(*connectionRequests)
+	if !ok {
+		return
+	}
+
+	switch stat.(type) {
+	case *stats.Begin:
+		active := conn.begin()
+		for {
+			peak := h.peak.Load()
+			if active <= peak || h.peak.CompareAndSwap(peak, active) {
+				break
+			}
+		}
+	case *stats.End:
+		conn.end()
+	}
+}
+
+// Collect implements prometheus Collector interface.
+func (h *ServerMaxConcurrentStreamsStatsHandler) Collect(in chan<- prometheus.Metric) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	var current int64
+	h.conns.each(func(conn *connectionRequests) {
+		if active := conn.active.Load(); active > current {
+			current = active
+		}
+	})
+	h.vec.WithLabelValues(sourceObserved).Set(float64(current))
+	h.vec.WithLabelValues(sourcePeak).Set(float64(h.peak.Load()))
+	h.vec.Collect(in)
+}
+
+type serverMaxConcurrentStreamsKey struct{}
@@ -0,0 +1,84 @@
+package promgrpc_test
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/stats"
+)
+
+func TestNewServerMaxConcurrentStreamsStatsHandler(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.NewStatsHandler(promgrpc.NewServerMaxConcurrentStreamsStatsHandler(promgrpc.NewServerMaxConcurrentStreamsGaugeVec(), 100))
+	ctx1 := h.TagConn(ctx, &stats.ConnTagInfo{
+		LocalAddr: &net.TCPAddr{
+			IP:   net.IPv4(1, 2, 3, 4),
+			Port: 80,
+		},
+		RemoteAddr: &net.TCPAddr{
+			IP:   net.IPv4(4, 3, 2, 1),
+			Port: 111,
+		},
+	})
+	h.HandleConn(ctx1, &stats.ConnBegin{})
+	ctx2 := h.TagConn(ctx, &stats.ConnTagInfo{
+		LocalAddr: &net.TCPAddr{
+			IP:   net.IPv4(1, 2, 3, 4),
+			Port: 90,
+		},
+		RemoteAddr: &net.TCPAddr{
+			IP:   net.IPv4(4, 3, 2, 1),
+			Port: 111,
+		},
+	})
+	h.HandleConn(ctx2, &stats.ConnBegin{})
+
+	begin := func(ctx context.Context) context.Context {
+		ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+			FullMethodName: "/service/Method",
+		})
+		h.HandleRPC(ctx, &stats.Begin{})
+		return ctx
+	}
+	rpc1 := begin(ctx1)
+	rpc2 := begin(ctx1)
+	h.HandleRPC(begin(ctx1), &stats.End{})
+	rpc3 := begin(ctx2)
+
+	const metadata = `
+		# HELP grpc_server_max_concurrent_streams Highest number of RPCs concurrently active on a single connection, currently and since the start, next to the configured limit.
+		# TYPE grpc_server_max_concurrent_streams gauge
+	`
+	expected := `
+		grpc_server_max_concurrent_streams{grpc_source="configured"} 100
+		grpc_server_max_concurrent_streams{grpc_source="observed"} 2
+		grpc_server_max_concurrent_streams{grpc_source="peak"} 3
+	`
+	// Collections do not affect each other, e.g. if there is more than one scraper.
+	for i := 0; i < 2; i++ {
+		if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_server_max_concurrent_streams"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, rpc := range []context.Context{rpc1, rpc2, rpc3} {
+		h.HandleRPC(rpc, &stats.End{})
+	}
+
+	// The peak is kept once RPCs end.
+	expected = `
+		grpc_server_max_concurrent_streams{grpc_source="configured"} 100
+		grpc_server_max_concurrent_streams{grpc_source="observed"} 0
+		grpc_server_max_concurrent_streams{grpc_source="peak"} 3
+	`
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_server_max_concurrent_streams"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,62 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewServerMessageReceivedCompressionRatioHistogramVec allocates a new Prometheus HistogramVec for the server and given set of options.
+func NewServerMessageReceivedCompressionRatioHistogramVec(opts ...CollectorOption) *prometheus.HistogramVec {
+	labels := []string{
+		labelClientUserAgent,
+		labelMethod,
+		labelService,
+	}
+	return newMessageReceivedCompressionRatioHistogramVec("server", labels, opts...)
+}
+
+// ServerMessageReceivedCompressionRatioStatsHandler is responsible for observing the ratio between uncompressed and compressed size of messages received.
+// A ratio lower than one means that compression increased the size of a message.
+// Empty messages are not observed.
+type ServerMessageReceivedCompressionRatioStatsHandler struct {
+	baseStatsHandler
+	vec prometheus.ObserverVec
+}
+
+// NewServerMessageReceivedCompressionRatioStatsHandler ...
+func NewServerMessageReceivedCompressionRatioStatsHandler(vec prometheus.ObserverVec, opts ...StatsHandlerOption) *ServerMessageReceivedCompressionRatioStatsHandler {
+	h := &ServerMessageReceivedCompressionRatioStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector:       vec,
+			supportedLabels: optionalLabelCompression,
+			options: statsHandlerOptions{
+				handleRPCLabelFn: serverMessageReceivedCompressionRatioLabels,
+			},
+		},
+		vec: vec,
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ServerMessageReceivedCompressionRatioStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if pay, ok := stat.(*stats.InPayload); ok {
+		switch {
+		case !stat.IsClient() && pay.CompressedLength > 0:
+			h.observe(ctx, h.vec, h.labelValues(ctx, stat), float64(pay.Length)/float64(pay.CompressedLength))
+		}
+	}
+}
+
+func serverMessageReceivedCompressionRatioLabels(ctx context.Context, _ stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	return []string{
+		tag.clientUserAgent,
+		tag.method,
+		tag.service,
+	}
+}
@@ -0,0 +1,94 @@
+package promgrpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewServerMessageReceivedIntervalHistogramVec allocates a new Prometheus HistogramVec for the server and given set of options.
+func NewServerMessageReceivedIntervalHistogramVec(opts ...CollectorOption) *prometheus.HistogramVec {
+	labels := []string{
+		labelClientUserAgent,
+		labelMethod,
+		labelService,
+	}
+	return newMessageReceivedIntervalHistogramVec("server", labels, opts...)
+}
+
+// ServerMessageReceivedIntervalStatsHandler is responsible for measuring gaps between consecutive messages received within a single stream.
+// It makes stalled streams visible, even if they eventually succeed.
+type ServerMessageReceivedIntervalStatsHandler struct {
+	baseStatsHandler
+	vec prometheus.ObserverVec
+}
+
+// NewServerMessageReceivedIntervalStatsHandler ...
+func NewServerMessageReceivedIntervalStatsHandler(vec prometheus.ObserverVec, opts ...StatsHandlerOption) *ServerMessageReceivedIntervalStatsHandler {
+	h := &ServerMessageReceivedIntervalStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+			options: statsHandlerOptions{
+				handleRPCLabelFn: serverMessageReceivedIntervalLabels,
+			},
+		},
+		vec: vec,
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// TagRPC implements stats Handler interface.
+func (h *ServerMessageReceivedIntervalStatsHandler) TagRPC(ctx context.Context, inf *stats.RPCTagInfo) context.Context {
+	ctx = h.baseStatsHandler.TagRPC(ctx, inf)
+	ctx = context.WithValue(ctx, serverMessageReceivedIntervalKey{}, &messageIntervalMark{})
+	return ctx
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ServerMessageReceivedIntervalStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if pay, ok := stat.(*stats.InPayload); ok {
+		switch {
+		case !stat.IsClient():
+			if mrk, ok := ctx.Value(serverMessageReceivedIntervalKey{}).(*messageIntervalMark); ok {
+				if interval, ok := mrk.next(pay.RecvTime); ok {
+					h.observe(ctx, h.vec, h.labelValues(ctx, stat), interval.Seconds())
+				}
+			}
+		}
+	}
+}
+
+func serverMessageReceivedIntervalLabels(ctx context.Context, _ stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	return []string{
+		tag.clientUserAgent,
+		tag.method,
+		tag.service,
+	}
+}
+
+type serverMessageReceivedIntervalKey struct{}
+
+// messageIntervalMark remembers when the last message in a given direction was seen.
+// gRPC does not allow concurrent calls of SendMsg nor RecvMsg on a single stream,
+// so there is no need to synchronize access to it.
+type messageIntervalMark struct {
+	last time.Time
+}
+
+// next records the given time and returns the interval from the previous message, if any.
+func (m *messageIntervalMark) next(t time.Time) (time.Duration, bool) {
+	if t.IsZero() {
+		t = time.Now()
+	}
+	prev := m.last
+	m.last = t
+	if prev.IsZero() {
+		return 0, false
+	}
+	return t.Sub(prev), true
+}
@@ -25,7 +25,8 @@ type ServerMessageReceivedSizeStatsHandler struct {
 func NewServerMessageReceivedSizeStatsHandler(vec prometheus.ObserverVec, opts ...StatsHandlerOption) *ServerMessageReceivedSizeStatsHandler {
 	h := &ServerMessageReceivedSizeStatsHandler{
 		baseStatsHandler: baseStatsHandler{
-			collector: vec,
+			collector:       vec,
+			supportedLabels: optionalLabelCompression | optionalLabelMessageType,
 			options: statsHandlerOptions{
 				handleRPCLabelFn: serverMessageReceivedSizeLabels,
 			},
@@ -42,13 +43,13 @@ func (h *ServerMessageReceivedSizeStatsHandler) HandleRPC(ctx context.Context, s
 	if pay, ok := stat.(*stats.InPayload); ok {
 		switch {
 		case !stat.IsClient():
-			h.vec.WithLabelValues(h.options.handleRPCLabelFn(ctx, stat)...).Observe(float64(pay.Length))
+			h.observe(ctx, h.vec, h.labelValues(ctx, stat), float64(pay.Length))
 		}
 	}
 }
 
 func serverMessageReceivedSizeLabels(ctx context.Context, _ stats.RPCStats) []string {
-	tag := ctx.Value(tagRPCKey).(rpcTagLabels)
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
 	return []string{
 		tag.clientUserAgent,
 		tag.method,
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/testutil"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/stats"
@@ -61,3 +62,100 @@ func TestNewServerMessageReceivedSizeStatsHandler(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestNewServerMessageReceivedSizeStatsHandler_compressionLabel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.ServerStatsHandler(
+		promgrpc.WithCompressionLabel(),
+	)
+	ctx = metadata.NewIncomingContext(ctx, metadata.MD{"user-agent": []string{"fake-user-agent"}})
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+		FullMethodName: "/service/Method",
+	})
+	h.HandleRPC(ctx, &stats.InHeader{})
+	h.HandleRPC(ctx, &stats.Begin{})
+	h.HandleRPC(ctx, &stats.InPayload{
+		Length: 5,
+	})
+	h.HandleRPC(ctx, &stats.End{})
+
+	const metadata = `
+		# HELP grpc_server_message_received_size_histogram_bytes TODO
+        # TYPE grpc_server_message_received_size_histogram_bytes histogram
+	`
+	expected := `
+		grpc_server_message_received_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_compression="identity",grpc_method="Method",grpc_service="service",le="0.005"} 0
+        grpc_server_message_received_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_compression="identity",grpc_method="Method",grpc_service="service",le="0.01"} 0
+        grpc_server_message_received_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_compression="identity",grpc_method="Method",grpc_service="service",le="0.025"} 0
+        grpc_server_message_received_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_compression="identity",grpc_method="Method",grpc_service="service",le="0.05"} 0
+        grpc_server_message_received_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_compression="identity",grpc_method="Method",grpc_service="service",le="0.1"} 0
+        grpc_server_message_received_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_compression="identity",grpc_method="Method",grpc_service="service",le="0.25"} 0
+        grpc_server_message_received_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_compression="identity",grpc_method="Method",grpc_service="service",le="0.5"} 0
+        grpc_server_message_received_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_compression="identity",grpc_method="Method",grpc_service="service",le="1"} 0
+        grpc_server_message_received_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_compression="identity",grpc_method="Method",grpc_service="service",le="2.5"} 0
+        grpc_server_message_received_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_compression="identity",grpc_method="Method",grpc_service="service",le="5"} 1
+        grpc_server_message_received_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_compression="identity",grpc_method="Method",grpc_service="service",le="10"} 1
+        grpc_server_message_received_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_compression="identity",grpc_method="Method",grpc_service="service",le="+Inf"} 1
+        grpc_server_message_received_size_histogram_bytes_sum{grpc_client_user_agent="fake-user-agent",grpc_compression="identity",grpc_method="Method",grpc_service="service"} 5
+        grpc_server_message_received_size_histogram_bytes_count{grpc_client_user_agent="fake-user-agent",grpc_compression="identity",grpc_method="Method",grpc_service="service"} 1
+	`
+
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_server_message_received_size_histogram_bytes"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewServerMessageReceivedSizeStatsHandler_nativeHistogram(t *testing.T) {
+	cases := map[string]struct {
+		opts    []promgrpc.CollectorOption
+		buckets int
+	}{
+		"alongside-classic-buckets": {
+			opts:    []promgrpc.CollectorOption{promgrpc.CollectorWithNativeHistogramBucketFactor(1.1)},
+			buckets: 11,
+		},
+		"alone": {
+			opts:    []promgrpc.CollectorOption{promgrpc.CollectorWithNativeHistogramBucketFactor(1.1), promgrpc.CollectorWithoutClassicBuckets()},
+			buckets: 0,
+		},
+	}
+
+	for hint, c := range cases {
+		t.Run(hint, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			opts := append(c.opts, promgrpc.CollectorWithNativeHistogramZeroThreshold(1), promgrpc.CollectorWithNativeHistogramMaxBucketNumber(100))
+			h := promgrpc.NewStatsHandler(promgrpc.NewServerMessageReceivedSizeStatsHandler(promgrpc.NewServerMessageReceivedSizeHistogramVec(opts...)))
+			reg := prometheus.NewRegistry()
+			registerCollector(t, reg, h)
+
+			ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+				FullMethodName: "/service/Method",
+			})
+			h.HandleRPC(ctx, &stats.InPayload{
+				Length: 1000,
+			})
+
+			families, err := reg.Gather()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(families) != 1 {
+				t.Fatalf("unexpected number of metric families: %d", len(families))
+			}
+			histogram := families[0].GetMetric()[0].GetHistogram()
+			if got := len(histogram.GetBucket()); got != c.buckets {
+				t.Errorf("wrong number of classic buckets, expected %d but got %d", c.buckets, got)
+			}
+			if histogram.GetZeroThreshold() != 1 {
+				t.Errorf("wrong zero threshold, expected 1 but got %f", histogram.GetZeroThreshold())
+			}
+			if len(histogram.GetPositiveSpan()) == 0 {
+				t.Error("native histogram buckets are missing")
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewServerMessageSentCompressionRatioHistogramVec allocates a new Prometheus HistogramVec for the server and given set of options.
+func NewServerMessageSentCompressionRatioHistogramVec(opts ...CollectorOption) *prometheus.HistogramVec {
+	labels := []string{
+		labelClientUserAgent,
+		labelMethod,
+		labelService,
+	}
+	return newMessageSentCompressionRatioHistogramVec("server", labels, opts...)
+}
+
+// ServerMessageSentCompressionRatioStatsHandler is responsible for observing the ratio between uncompressed and compressed size of messages sent.
+// A ratio lower than one means that compression increased the size of a message.
+// Empty messages are not observed.
+type ServerMessageSentCompressionRatioStatsHandler struct {
+	baseStatsHandler
+	vec prometheus.ObserverVec
+}
+
+// NewServerMessageSentCompressionRatioStatsHandler ...
+func NewServerMessageSentCompressionRatioStatsHandler(vec prometheus.ObserverVec, opts ...StatsHandlerOption) *ServerMessageSentCompressionRatioStatsHandler {
+	h := &ServerMessageSentCompressionRatioStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector:       vec,
+			supportedLabels: optionalLabelCompression,
+			options: statsHandlerOptions{
+				handleRPCLabelFn: serverMessageSentCompressionRatioLabels,
+			},
+		},
+		vec: vec,
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ServerMessageSentCompressionRatioStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if pay, ok := stat.(*stats.OutPayload); ok {
+		switch {
+		case !stat.IsClient() && pay.CompressedLength > 0:
+			h.observe(ctx, h.vec, h.labelValues(ctx, stat), float64(pay.Length)/float64(pay.CompressedLength))
+		}
+	}
+}
+
+func serverMessageSentCompressionRatioLabels(ctx context.Context, _ stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	return []string{
+		tag.clientUserAgent,
+		tag.method,
+		tag.service,
+	}
+}
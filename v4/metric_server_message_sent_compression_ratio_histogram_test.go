@@ -0,0 +1,72 @@
+package promgrpc_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+)
+
+func TestNewServerMessageSentCompressionRatioStatsHandler(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.NewStatsHandler(promgrpc.NewServerMessageSentCompressionRatioStatsHandler(
+		promgrpc.NewServerMessageSentCompressionRatioHistogramVec(promgrpc.WithCompressionLabel()),
+		promgrpc.WithCompressionLabel(),
+	))
+	ctx = metadata.NewIncomingContext(ctx, metadata.MD{"user-agent": []string{"fake-user-agent"}})
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+		FullMethodName: "/service/Method",
+		FailFast:       true,
+	})
+	h.HandleRPC(ctx, &stats.OutHeader{
+		Compression: "gzip",
+	})
+	h.HandleRPC(ctx, &stats.OutPayload{
+		Length:           300,
+		CompressedLength: 100,
+	})
+	h.HandleRPC(ctx, &stats.OutPayload{
+		Length:           100,
+		CompressedLength: 200,
+	})
+	h.HandleRPC(ctx, &stats.OutPayload{
+		Length:           0,
+		CompressedLength: 0,
+	})
+	h.HandleRPC(ctx, &stats.OutPayload{
+		Client:           true,
+		Length:           300,
+		CompressedLength: 100,
+	})
+
+	const metadata = `
+		# HELP grpc_server_message_sent_compression_ratio_histogram Ratio between uncompressed and compressed size of messages sent.
+        # TYPE grpc_server_message_sent_compression_ratio_histogram histogram
+	`
+	expected := `
+		grpc_server_message_sent_compression_ratio_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_compression="gzip",grpc_method="Method",grpc_service="service",le="0.5"} 1
+        grpc_server_message_sent_compression_ratio_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_compression="gzip",grpc_method="Method",grpc_service="service",le="0.75"} 1
+        grpc_server_message_sent_compression_ratio_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_compression="gzip",grpc_method="Method",grpc_service="service",le="1"} 1
+        grpc_server_message_sent_compression_ratio_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_compression="gzip",grpc_method="Method",grpc_service="service",le="1.25"} 1
+        grpc_server_message_sent_compression_ratio_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_compression="gzip",grpc_method="Method",grpc_service="service",le="1.5"} 1
+        grpc_server_message_sent_compression_ratio_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_compression="gzip",grpc_method="Method",grpc_service="service",le="2"} 1
+        grpc_server_message_sent_compression_ratio_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_compression="gzip",grpc_method="Method",grpc_service="service",le="3"} 2
+        grpc_server_message_sent_compression_ratio_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_compression="gzip",grpc_method="Method",grpc_service="service",le="5"} 2
+        grpc_server_message_sent_compression_ratio_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_compression="gzip",grpc_method="Method",grpc_service="service",le="10"} 2
+        grpc_server_message_sent_compression_ratio_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_compression="gzip",grpc_method="Method",grpc_service="service",le="20"} 2
+        grpc_server_message_sent_compression_ratio_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_compression="gzip",grpc_method="Method",grpc_service="service",le="+Inf"} 2
+        grpc_server_message_sent_compression_ratio_histogram_sum{grpc_client_user_agent="fake-user-agent",grpc_compression="gzip",grpc_method="Method",grpc_service="service"} 3.5
+        grpc_server_message_sent_compression_ratio_histogram_count{grpc_client_user_agent="fake-user-agent",grpc_compression="gzip",grpc_method="Method",grpc_service="service"} 2
+	`
+
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_server_message_sent_compression_ratio_histogram"); err != nil {
+		t.Fatal(err)
+	}
+}
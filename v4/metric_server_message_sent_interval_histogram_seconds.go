@@ -0,0 +1,73 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewServerMessageSentIntervalHistogramVec allocates a new Prometheus HistogramVec for the server and given set of options.
+func NewServerMessageSentIntervalHistogramVec(opts ...CollectorOption) *prometheus.HistogramVec {
+	labels := []string{
+		labelClientUserAgent,
+		labelMethod,
+		labelService,
+	}
+	return newMessageSentIntervalHistogramVec("server", labels, opts...)
+}
+
+// ServerMessageSentIntervalStatsHandler is responsible for measuring gaps between consecutive messages sent within a single stream.
+// It makes stalled streams visible, even if they eventually succeed.
+type ServerMessageSentIntervalStatsHandler struct {
+	baseStatsHandler
+	vec prometheus.ObserverVec
+}
+
+// NewServerMessageSentIntervalStatsHandler ...
+func NewServerMessageSentIntervalStatsHandler(vec prometheus.ObserverVec, opts ...StatsHandlerOption) *ServerMessageSentIntervalStatsHandler {
+	h := &ServerMessageSentIntervalStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+			options: statsHandlerOptions{
+				handleRPCLabelFn: serverMessageSentIntervalLabels,
+			},
+		},
+		vec: vec,
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// TagRPC implements stats Handler interface.
+func (h *ServerMessageSentIntervalStatsHandler) TagRPC(ctx context.Context, inf *stats.RPCTagInfo) context.Context {
+	ctx = h.baseStatsHandler.TagRPC(ctx, inf)
+	ctx = context.WithValue(ctx, serverMessageSentIntervalKey{}, &messageIntervalMark{})
+	return ctx
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ServerMessageSentIntervalStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if pay, ok := stat.(*stats.OutPayload); ok {
+		switch {
+		case !stat.IsClient():
+			if mrk, ok := ctx.Value(serverMessageSentIntervalKey{}).(*messageIntervalMark); ok {
+				if interval, ok := mrk.next(pay.SentTime); ok {
+					h.observe(ctx, h.vec, h.labelValues(ctx, stat), interval.Seconds())
+				}
+			}
+		}
+	}
+}
+
+func serverMessageSentIntervalLabels(ctx context.Context, _ stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	return []string{
+		tag.clientUserAgent,
+		tag.method,
+		tag.service,
+	}
+}
+
+type serverMessageSentIntervalKey struct{}
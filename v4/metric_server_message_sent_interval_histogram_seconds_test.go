@@ -0,0 +1,65 @@
+package promgrpc_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+)
+
+func TestNewServerMessageSentIntervalStatsHandler(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	bt := time.Now()
+
+	h := promgrpc.NewStatsHandler(promgrpc.NewServerMessageSentIntervalStatsHandler(promgrpc.NewServerMessageSentIntervalHistogramVec()))
+	ctx = metadata.NewIncomingContext(ctx, metadata.MD{"user-agent": []string{"fake-user-agent"}})
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+		FullMethodName: "/service/Method",
+		FailFast:       true,
+	})
+	h.HandleRPC(ctx, &stats.OutPayload{
+		SentTime: bt,
+	})
+	h.HandleRPC(ctx, &stats.OutPayload{
+		SentTime: bt.Add(1 * time.Second),
+	})
+	h.HandleRPC(ctx, &stats.OutPayload{
+		SentTime: bt.Add(3 * time.Second),
+	})
+	h.HandleRPC(ctx, &stats.OutPayload{
+		Client:   true,
+		SentTime: bt.Add(10 * time.Second),
+	})
+
+	const metadata = `
+		# HELP grpc_server_message_sent_interval_histogram_seconds Time elapsed between two consecutive messages sent within the same RPC.
+        # TYPE grpc_server_message_sent_interval_histogram_seconds histogram
+	`
+	expected := `
+		grpc_server_message_sent_interval_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_service="service",le="0.005"} 0
+        grpc_server_message_sent_interval_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_service="service",le="0.01"} 0
+        grpc_server_message_sent_interval_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_service="service",le="0.025"} 0
+        grpc_server_message_sent_interval_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_service="service",le="0.05"} 0
+        grpc_server_message_sent_interval_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_service="service",le="0.1"} 0
+        grpc_server_message_sent_interval_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_service="service",le="0.25"} 0
+        grpc_server_message_sent_interval_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_service="service",le="0.5"} 0
+        grpc_server_message_sent_interval_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_service="service",le="1"} 1
+        grpc_server_message_sent_interval_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_service="service",le="2.5"} 2
+        grpc_server_message_sent_interval_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_service="service",le="5"} 2
+        grpc_server_message_sent_interval_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_service="service",le="10"} 2
+        grpc_server_message_sent_interval_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_service="service",le="+Inf"} 2
+        grpc_server_message_sent_interval_histogram_seconds_sum{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_service="service"} 3
+        grpc_server_message_sent_interval_histogram_seconds_count{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_service="service"} 2
+	`
+
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_server_message_sent_interval_histogram_seconds"); err != nil {
+		t.Fatal(err)
+	}
+}
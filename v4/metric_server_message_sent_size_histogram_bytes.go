@@ -25,7 +25,8 @@ type ServerMessageSentSizeStatsHandler struct {
 func NewServerMessageSentSizeStatsHandler(vec prometheus.ObserverVec, opts ...StatsHandlerOption) *ServerMessageSentSizeStatsHandler {
 	h := &ServerMessageSentSizeStatsHandler{
 		baseStatsHandler: baseStatsHandler{
-			collector: vec,
+			collector:       vec,
+			supportedLabels: optionalLabelCompression | optionalLabelMessageType,
 			options: statsHandlerOptions{
 				handleRPCLabelFn: serverMessageSentSizeLabels,
 			},
@@ -42,13 +43,13 @@ func (h *ServerMessageSentSizeStatsHandler) HandleRPC(ctx context.Context, stat
 	if pay, ok := stat.(*stats.OutPayload); ok {
 		switch {
 		case !stat.IsClient():
-			h.vec.WithLabelValues(h.options.handleRPCLabelFn(ctx, stat)...).Observe(float64(pay.Length))
+			h.observe(ctx, h.vec, h.labelValues(ctx, stat), float64(pay.Length))
 		}
 	}
 }
 
 func serverMessageSentSizeLabels(ctx context.Context, _ stats.RPCStats) []string {
-	tag := ctx.Value(tagRPCKey).(rpcTagLabels)
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
 	return []string{
 		tag.clientUserAgent,
 		tag.method,
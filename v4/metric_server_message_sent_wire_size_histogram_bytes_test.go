@@ -0,0 +1,65 @@
+package promgrpc_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+)
+
+func TestNewServerMessageSentWireSizeStatsHandler(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.NewStatsHandler(promgrpc.NewServerMessageSentWireSizeStatsHandler(
+		promgrpc.NewServerMessageSentWireSizeHistogramVec(promgrpc.WithCompressionLabel()),
+		promgrpc.WithCompressionLabel(),
+	))
+	ctx = metadata.NewIncomingContext(ctx, metadata.MD{"user-agent": []string{"fake-user-agent"}})
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+		FullMethodName: "/service/Method",
+		FailFast:       true,
+	})
+	h.HandleRPC(ctx, &stats.OutHeader{
+		Compression: "gzip",
+	})
+	h.HandleRPC(ctx, &stats.OutPayload{
+		WireLength: 105,
+	})
+	h.HandleRPC(ctx, &stats.OutPayload{
+		WireLength: 205,
+	})
+	h.HandleRPC(ctx, &stats.OutPayload{
+		Client:     true,
+		WireLength: 105,
+	})
+
+	const metadata = `
+		# HELP grpc_server_message_sent_wire_size_histogram_bytes Size of messages sent, after compression and including gRPC framing.
+        # TYPE grpc_server_message_sent_wire_size_histogram_bytes histogram
+	`
+	expected := `
+		grpc_server_message_sent_wire_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_compression="gzip",grpc_method="Method",grpc_service="service",le="64"} 0
+        grpc_server_message_sent_wire_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_compression="gzip",grpc_method="Method",grpc_service="service",le="256"} 2
+        grpc_server_message_sent_wire_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_compression="gzip",grpc_method="Method",grpc_service="service",le="1024"} 2
+        grpc_server_message_sent_wire_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_compression="gzip",grpc_method="Method",grpc_service="service",le="4096"} 2
+        grpc_server_message_sent_wire_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_compression="gzip",grpc_method="Method",grpc_service="service",le="16384"} 2
+        grpc_server_message_sent_wire_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_compression="gzip",grpc_method="Method",grpc_service="service",le="65536"} 2
+        grpc_server_message_sent_wire_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_compression="gzip",grpc_method="Method",grpc_service="service",le="262144"} 2
+        grpc_server_message_sent_wire_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_compression="gzip",grpc_method="Method",grpc_service="service",le="1048576"} 2
+        grpc_server_message_sent_wire_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_compression="gzip",grpc_method="Method",grpc_service="service",le="4194304"} 2
+        grpc_server_message_sent_wire_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_compression="gzip",grpc_method="Method",grpc_service="service",le="16777216"} 2
+        grpc_server_message_sent_wire_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_compression="gzip",grpc_method="Method",grpc_service="service",le="+Inf"} 2
+        grpc_server_message_sent_wire_size_histogram_bytes_sum{grpc_client_user_agent="fake-user-agent",grpc_compression="gzip",grpc_method="Method",grpc_service="service"} 310
+        grpc_server_message_sent_wire_size_histogram_bytes_count{grpc_client_user_agent="fake-user-agent",grpc_compression="gzip",grpc_method="Method",grpc_service="service"} 2
+	`
+
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_server_message_sent_wire_size_histogram_bytes"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,96 @@
+package promgrpc
+
+import (
+	"context"
+	"sync/atomic"
+
+	"google.golang.org/grpc/status"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewServerMessagesReceivedPerRequestHistogramVec allocates a new Prometheus HistogramVec for the server and given set of options.
+func NewServerMessagesReceivedPerRequestHistogramVec(opts ...CollectorOption) *prometheus.HistogramVec {
+	labels := []string{
+		labelClientUserAgent,
+		labelCode,
+		labelMethod,
+		labelService,
+	}
+	return newMessagesReceivedPerRequestHistogramVec("server", labels, opts...)
+}
+
+// ServerMessagesReceivedPerRequestStatsHandler is responsible for observing the number of messages received within a single RPC.
+// The total is observed once the RPC ends.
+type ServerMessagesReceivedPerRequestStatsHandler struct {
+	baseStatsHandler
+	vec prometheus.ObserverVec
+}
+
+// NewServerMessagesReceivedPerRequestStatsHandler ...
+func NewServerMessagesReceivedPerRequestStatsHandler(vec prometheus.ObserverVec, opts ...StatsHandlerOption) *ServerMessagesReceivedPerRequestStatsHandler {
+	h := &ServerMessagesReceivedPerRequestStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+			options: statsHandlerOptions{
+				handleRPCLabelFn: serverMessagesReceivedPerRequestLabels,
+			},
+		},
+		vec: vec,
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// TagRPC implements stats Handler interface.
+func (h *ServerMessagesReceivedPerRequestStatsHandler) TagRPC(ctx context.Context, inf *stats.RPCTagInfo) context.Context {
+	ctx = h.baseStatsHandler.TagRPC(ctx, inf)
+	ctx = context.WithValue(ctx, serverMessagesReceivedPerRequestKey{}, &requestTotalMark{})
+	return ctx
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ServerMessagesReceivedPerRequestStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if stat.IsClient() {
+		return
+	}
+
+	switch stat.(type) {
+	case *stats.InPayload:
+		if mrk, ok := ctx.Value(serverMessagesReceivedPerRequestKey{}).(*requestTotalMark); ok {
+			mrk.add(1)
+		}
+	case *stats.End:
+		if mrk, ok := ctx.Value(serverMessagesReceivedPerRequestKey{}).(*requestTotalMark); ok {
+			h.observe(ctx, h.vec, h.labelValues(ctx, stat), float64(mrk.load()))
+		}
+	}
+}
+
+func serverMessagesReceivedPerRequestLabels(ctx context.Context, stat stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	return []string{
+		tag.clientUserAgent,
+		status.Code(stat.(*stats.End).Error).String(),
+		tag.method,
+		tag.service,
+	}
+}
+
+type serverMessagesReceivedPerRequestKey struct{}
+
+// requestTotalMark accumulates a value over the lifetime of a single RPC.
+// End can be reported by a different goroutine than payloads, hence the atomic.
+type requestTotalMark struct {
+	total atomic.Int64
+}
+
+func (m *requestTotalMark) add(n int64) {
+	m.total.Add(n)
+}
+
+func (m *requestTotalMark) load() int64 {
+	return m.total.Load()
+}
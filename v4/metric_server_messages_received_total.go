@@ -27,7 +27,8 @@ type ServerMessagesReceivedTotalStatsHandler struct {
 func NewServerMessagesReceivedTotalStatsHandler(vec *prometheus.CounterVec, opts ...StatsHandlerOption) *ServerMessagesReceivedTotalStatsHandler {
 	h := &ServerMessagesReceivedTotalStatsHandler{
 		baseStatsHandler: baseStatsHandler{
-			collector: vec,
+			collector:       vec,
+			supportedLabels: optionalLabelMessageType,
 			options: statsHandlerOptions{
 				handleRPCLabelFn: serverMessagesReceivedTotalLabels,
 			},
@@ -44,13 +45,13 @@ func (h *ServerMessagesReceivedTotalStatsHandler) HandleRPC(ctx context.Context,
 	if _, ok := stat.(*stats.InPayload); ok {
 		switch {
 		case !stat.IsClient():
-			h.vec.WithLabelValues(h.options.handleRPCLabelFn(ctx, stat)...).Inc()
+			h.inc(ctx, h.vec.WithLabelValues(h.labelValues(ctx, stat)...))
 		}
 	}
 }
 
 func serverMessagesReceivedTotalLabels(ctx context.Context, _ stats.RPCStats) []string {
-	tag := ctx.Value(tagRPCKey).(rpcTagLabels)
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
 	return []string{
 		tag.clientUserAgent,
 		tag.method,
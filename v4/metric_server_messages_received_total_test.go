@@ -10,6 +10,9 @@ import (
 	"github.com/prometheus/client_golang/prometheus/testutil"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/stats"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 func TestNewServerMessagesReceivedTotalStatsHandler(t *testing.T) {
@@ -41,3 +44,54 @@ func TestNewServerMessagesReceivedTotalStatsHandler(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestNewServerMessagesReceivedTotalStatsHandler_messageTypeLabel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	packed, err := anypb.New(wrapperspb.String("example"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := promgrpc.NewStatsHandler(promgrpc.NewServerMessagesReceivedTotalStatsHandler(
+		promgrpc.NewServerMessagesReceivedTotalCounterVec(promgrpc.WithMessageTypeLabel()),
+		promgrpc.WithMessageTypeLabel(),
+	))
+	ctx = metadata.NewIncomingContext(ctx, metadata.MD{"user-agent": []string{"fake-user-agent"}})
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+		FullMethodName: "/service/Method",
+	})
+	h.HandleRPC(ctx, &stats.InPayload{
+		Payload: packed,
+	})
+	h.HandleRPC(ctx, &stats.InPayload{
+		Payload: structpb.NewStructValue(&structpb.Struct{}),
+	})
+	h.HandleRPC(ctx, &stats.InPayload{
+		Payload: structpb.NewNumberValue(1),
+	})
+	h.HandleRPC(ctx, &stats.InPayload{
+		Payload: []byte("raw"),
+	})
+	// A type URL chosen by a client must not create a series of its own.
+	h.HandleRPC(ctx, &stats.InPayload{
+		Payload: &anypb.Any{TypeUrl: "type.googleapis.com/random.Type123"},
+	})
+
+	const metadata = `
+		# HELP grpc_server_messages_received_total TODO
+        # TYPE grpc_server_messages_received_total counter
+	`
+	expected := `
+		grpc_server_messages_received_total{grpc_client_user_agent="fake-user-agent",grpc_message_type="google.protobuf.Any",grpc_method="Method",grpc_service="service"} 1
+		grpc_server_messages_received_total{grpc_client_user_agent="fake-user-agent",grpc_message_type="google.protobuf.StringValue",grpc_method="Method",grpc_service="service"} 1
+		grpc_server_messages_received_total{grpc_client_user_agent="fake-user-agent",grpc_message_type="google.protobuf.Struct",grpc_method="Method",grpc_service="service"} 1
+		grpc_server_messages_received_total{grpc_client_user_agent="fake-user-agent",grpc_message_type="google.protobuf.Value",grpc_method="Method",grpc_service="service"} 1
+		grpc_server_messages_received_total{grpc_client_user_agent="fake-user-agent",grpc_message_type="n/a",grpc_method="Method",grpc_service="service"} 1
+	`
+
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_server_messages_received_total"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,81 @@
+package promgrpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc/status"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewServerMessagesSentPerRequestHistogramVec allocates a new Prometheus HistogramVec for the server and given set of options.
+func NewServerMessagesSentPerRequestHistogramVec(opts ...CollectorOption) *prometheus.HistogramVec {
+	labels := []string{
+		labelClientUserAgent,
+		labelCode,
+		labelMethod,
+		labelService,
+	}
+	return newMessagesSentPerRequestHistogramVec("server", labels, opts...)
+}
+
+// ServerMessagesSentPerRequestStatsHandler is responsible for observing the number of messages sent within a single RPC.
+// The total is observed once the RPC ends.
+type ServerMessagesSentPerRequestStatsHandler struct {
+	baseStatsHandler
+	vec prometheus.ObserverVec
+}
+
+// NewServerMessagesSentPerRequestStatsHandler ...
+func NewServerMessagesSentPerRequestStatsHandler(vec prometheus.ObserverVec, opts ...StatsHandlerOption) *ServerMessagesSentPerRequestStatsHandler {
+	h := &ServerMessagesSentPerRequestStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+			options: statsHandlerOptions{
+				handleRPCLabelFn: serverMessagesSentPerRequestLabels,
+			},
+		},
+		vec: vec,
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// TagRPC implements stats Handler interface.
+func (h *ServerMessagesSentPerRequestStatsHandler) TagRPC(ctx context.Context, inf *stats.RPCTagInfo) context.Context {
+	ctx = h.baseStatsHandler.TagRPC(ctx, inf)
+	ctx = context.WithValue(ctx, serverMessagesSentPerRequestKey{}, &requestTotalMark{})
+	return ctx
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ServerMessagesSentPerRequestStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if stat.IsClient() {
+		return
+	}
+
+	switch stat.(type) {
+	case *stats.OutPayload:
+		if mrk, ok := ctx.Value(serverMessagesSentPerRequestKey{}).(*requestTotalMark); ok {
+			mrk.add(1)
+		}
+	case *stats.End:
+		if mrk, ok := ctx.Value(serverMessagesSentPerRequestKey{}).(*requestTotalMark); ok {
+			h.observe(ctx, h.vec, h.labelValues(ctx, stat), float64(mrk.load()))
+		}
+	}
+}
+
+func serverMessagesSentPerRequestLabels(ctx context.Context, stat stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	return []string{
+		tag.clientUserAgent,
+		status.Code(stat.(*stats.End).Error).String(),
+		tag.method,
+		tag.service,
+	}
+}
+
+type serverMessagesSentPerRequestKey struct{}
@@ -27,7 +27,8 @@ type ServerMessagesSentTotalStatsHandler struct {
 func NewServerMessagesSentTotalStatsHandler(vec *prometheus.CounterVec, opts ...StatsHandlerOption) *ServerMessagesSentTotalStatsHandler {
 	h := &ServerMessagesSentTotalStatsHandler{
 		baseStatsHandler: baseStatsHandler{
-			collector: vec,
+			collector:       vec,
+			supportedLabels: optionalLabelMessageType,
 			options: statsHandlerOptions{
 				handleRPCLabelFn: serverMessagesSentTotalLabels,
 			},
@@ -44,13 +45,13 @@ func (h *ServerMessagesSentTotalStatsHandler) HandleRPC(ctx context.Context, sta
 	if _, ok := stat.(*stats.OutPayload); ok {
 		switch {
 		case !stat.IsClient():
-			h.vec.WithLabelValues(h.options.handleRPCLabelFn(ctx, stat)...).Inc()
+			h.inc(ctx, h.vec.WithLabelValues(h.labelValues(ctx, stat)...))
 		}
 	}
 }
 
 func serverMessagesSentTotalLabels(ctx context.Context, _ stats.RPCStats) []string {
-	tag := ctx.Value(tagRPCKey).(rpcTagLabels)
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
 	return []string{
 		tag.clientUserAgent,
 		tag.method,
@@ -0,0 +1,63 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewServerMetadataReceivedKeysHistogramVec allocates a new Prometheus HistogramVec for the server and given set of options.
+func NewServerMetadataReceivedKeysHistogramVec(opts ...CollectorOption) *prometheus.HistogramVec {
+	labels := []string{
+		// keep alphabetical order
+		labelClientUserAgent,
+		labelMetadataKind,
+		labelMethod,
+		labelService,
+	}
+	return newMetadataReceivedKeysHistogramVec("server", labels, opts...)
+}
+
+// ServerMetadataReceivedKeysStatsHandler is responsible for observing the number of metadata keys received within request headers.
+type ServerMetadataReceivedKeysStatsHandler struct {
+	baseStatsHandler
+	vec prometheus.ObserverVec
+}
+
+// NewServerMetadataReceivedKeysStatsHandler ...
+func NewServerMetadataReceivedKeysStatsHandler(vec prometheus.ObserverVec, opts ...StatsHandlerOption) *ServerMetadataReceivedKeysStatsHandler {
+	h := &ServerMetadataReceivedKeysStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+			options: statsHandlerOptions{
+				handleRPCLabelFn: serverMetadataReceivedKeysLabels,
+			},
+		},
+		vec: vec,
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ServerMetadataReceivedKeysStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	switch pay := stat.(type) {
+	case *stats.InHeader:
+		if !stat.IsClient() {
+			h.observe(ctx, h.vec, h.labelValues(ctx, stat), float64(len(pay.Header)))
+		}
+	}
+}
+
+func serverMetadataReceivedKeysLabels(ctx context.Context, stat stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	// keep alphabetical order
+	return []string{
+		tag.clientUserAgent,
+		metadataKind(stat),
+		tag.method,
+		tag.service,
+	}
+}
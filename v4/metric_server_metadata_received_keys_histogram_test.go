@@ -0,0 +1,57 @@
+package promgrpc_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+)
+
+func TestNewServerMetadataReceivedKeysStatsHandler(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.NewStatsHandler(promgrpc.NewServerMetadataReceivedKeysStatsHandler(promgrpc.NewServerMetadataReceivedKeysHistogramVec()))
+	ctx = metadata.NewIncomingContext(ctx, metadata.MD{"user-agent": []string{"fake-user-agent"}})
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+		FullMethodName: "/service/Method",
+		FailFast:       true,
+	})
+	h.HandleRPC(ctx, &stats.InHeader{
+		Header: metadata.MD{
+			"user-agent":    []string{"fake-user-agent"},
+			"authorization": []string{"Bearer token"},
+		},
+	})
+	h.HandleRPC(ctx, &stats.InHeader{
+		Client: true,
+		Header: metadata.MD{"key": []string{"value"}},
+	})
+
+	const metadata = `
+		# HELP grpc_server_metadata_received_keys_histogram Number of metadata keys received within headers and trailers.
+        # TYPE grpc_server_metadata_received_keys_histogram histogram
+	`
+	expected := `
+		grpc_server_metadata_received_keys_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service",le="1"} 0
+        grpc_server_metadata_received_keys_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service",le="2"} 1
+        grpc_server_metadata_received_keys_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service",le="4"} 1
+        grpc_server_metadata_received_keys_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service",le="8"} 1
+        grpc_server_metadata_received_keys_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service",le="16"} 1
+        grpc_server_metadata_received_keys_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service",le="32"} 1
+        grpc_server_metadata_received_keys_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service",le="64"} 1
+        grpc_server_metadata_received_keys_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service",le="128"} 1
+        grpc_server_metadata_received_keys_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service",le="+Inf"} 1
+        grpc_server_metadata_received_keys_histogram_sum{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service"} 2
+        grpc_server_metadata_received_keys_histogram_count{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service"} 1
+	`
+
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_server_metadata_received_keys_histogram"); err != nil {
+		t.Fatal(err)
+	}
+}
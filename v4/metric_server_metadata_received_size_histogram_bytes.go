@@ -0,0 +1,91 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+)
+
+// NewServerMetadataReceivedSizeHistogramVec allocates a new Prometheus HistogramVec for the server and given set of options.
+func NewServerMetadataReceivedSizeHistogramVec(opts ...CollectorOption) *prometheus.HistogramVec {
+	labels := []string{
+		// keep alphabetical order
+		labelClientUserAgent,
+		labelMetadataKind,
+		labelMethod,
+		labelService,
+	}
+	return newMetadataReceivedSizeHistogramVec("server", labels, opts...)
+}
+
+// ServerMetadataReceivedSizeStatsHandler is responsible for observing the size of incoming request headers.
+// The size is computed the same way HTTP/2 computes a header list size, before HPACK compression,
+// so that it is comparable with the size of sent metadata, see ServerMetadataSentSizeStatsHandler.
+// Large metadata, e.g. authentication tokens or tracing baggage, can lead to header list size limit violations.
+type ServerMetadataReceivedSizeStatsHandler struct {
+	baseStatsHandler
+	vec prometheus.ObserverVec
+}
+
+// NewServerMetadataReceivedSizeStatsHandler ...
+func NewServerMetadataReceivedSizeStatsHandler(vec prometheus.ObserverVec, opts ...StatsHandlerOption) *ServerMetadataReceivedSizeStatsHandler {
+	h := &ServerMetadataReceivedSizeStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+			options: statsHandlerOptions{
+				handleRPCLabelFn: serverMetadataReceivedSizeLabels,
+			},
+		},
+		vec: vec,
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ServerMetadataReceivedSizeStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	switch pay := stat.(type) {
+	case *stats.InHeader:
+		if !stat.IsClient() {
+			h.observe(ctx, h.vec, h.labelValues(ctx, stat), float64(metadataSize(pay.Header)))
+		}
+	}
+}
+
+func serverMetadataReceivedSizeLabels(ctx context.Context, stat stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	// keep alphabetical order
+	return []string{
+		tag.clientUserAgent,
+		metadataKind(stat),
+		tag.method,
+		tag.service,
+	}
+}
+
+// metadataKind returns a kind of metadata a given event carries.
+func metadataKind(stat stats.RPCStats) string {
+	switch stat.(type) {
+	case *stats.InHeader, *stats.OutHeader:
+		return metadataKindHeader
+	case *stats.InTrailer, *stats.OutTrailer:
+		return metadataKindTrailer
+	default:
+		return notAvailable
+	}
+}
+
+// metadataSize estimates the size of metadata the same way HTTP/2 computes a header list size (RFC 7540, Section 6.5.2).
+// Each field contributes the length of its name and value plus an overhead of 32 bytes.
+func metadataSize(md metadata.MD) int {
+	var size int
+	for key, values := range md {
+		for _, value := range values {
+			size += len(key) + len(value) + 32
+		}
+	}
+	return size
+}
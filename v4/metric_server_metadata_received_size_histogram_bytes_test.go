@@ -0,0 +1,59 @@
+package promgrpc_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+)
+
+func TestNewServerMetadataReceivedSizeStatsHandler(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.NewStatsHandler(promgrpc.NewServerMetadataReceivedSizeStatsHandler(promgrpc.NewServerMetadataReceivedSizeHistogramVec()))
+	ctx = metadata.NewIncomingContext(ctx, metadata.MD{"user-agent": []string{"fake-user-agent"}})
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+		FullMethodName: "/service/Method",
+		FailFast:       true,
+	})
+	h.HandleRPC(ctx, &stats.InHeader{
+		WireLength: 60,
+		Header:     metadata.MD{"user-agent": []string{"fake-user-agent"}, "content-type": []string{"application/grpc"}},
+	})
+	h.HandleRPC(ctx, &stats.InHeader{
+		Client:     true,
+		WireLength: 1000,
+	})
+
+	const metadata = `
+		# HELP grpc_server_metadata_received_size_histogram_bytes Size of headers and trailers received, computed as an HTTP/2 header list size.
+        # TYPE grpc_server_metadata_received_size_histogram_bytes histogram
+	`
+	expected := `
+		grpc_server_metadata_received_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service",le="32"} 0
+        grpc_server_metadata_received_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service",le="64"} 0
+        grpc_server_metadata_received_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service",le="128"} 1
+        grpc_server_metadata_received_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service",le="256"} 1
+        grpc_server_metadata_received_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service",le="512"} 1
+        grpc_server_metadata_received_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service",le="1024"} 1
+        grpc_server_metadata_received_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service",le="2048"} 1
+        grpc_server_metadata_received_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service",le="4096"} 1
+        grpc_server_metadata_received_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service",le="8192"} 1
+        grpc_server_metadata_received_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service",le="16384"} 1
+        grpc_server_metadata_received_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service",le="32768"} 1
+        grpc_server_metadata_received_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service",le="65536"} 1
+        grpc_server_metadata_received_size_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service",le="+Inf"} 1
+        grpc_server_metadata_received_size_histogram_bytes_sum{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service"} 117
+        grpc_server_metadata_received_size_histogram_bytes_count{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service"} 1
+	`
+
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_server_metadata_received_size_histogram_bytes"); err != nil {
+		t.Fatal(err)
+	}
+}
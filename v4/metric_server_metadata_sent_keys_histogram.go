@@ -0,0 +1,67 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewServerMetadataSentKeysHistogramVec allocates a new Prometheus HistogramVec for the server and given set of options.
+func NewServerMetadataSentKeysHistogramVec(opts ...CollectorOption) *prometheus.HistogramVec {
+	labels := []string{
+		// keep alphabetical order
+		labelClientUserAgent,
+		labelMetadataKind,
+		labelMethod,
+		labelService,
+	}
+	return newMetadataSentKeysHistogramVec("server", labels, opts...)
+}
+
+// ServerMetadataSentKeysStatsHandler is responsible for observing the number of metadata keys sent within response headers and trailers.
+type ServerMetadataSentKeysStatsHandler struct {
+	baseStatsHandler
+	vec prometheus.ObserverVec
+}
+
+// NewServerMetadataSentKeysStatsHandler ...
+func NewServerMetadataSentKeysStatsHandler(vec prometheus.ObserverVec, opts ...StatsHandlerOption) *ServerMetadataSentKeysStatsHandler {
+	h := &ServerMetadataSentKeysStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+			options: statsHandlerOptions{
+				handleRPCLabelFn: serverMetadataSentKeysLabels,
+			},
+		},
+		vec: vec,
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ServerMetadataSentKeysStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	switch pay := stat.(type) {
+	case *stats.OutHeader:
+		if !stat.IsClient() {
+			h.observe(ctx, h.vec, h.labelValues(ctx, stat), float64(len(pay.Header)))
+		}
+	case *stats.OutTrailer:
+		if !stat.IsClient() {
+			h.observe(ctx, h.vec, h.labelValues(ctx, stat), float64(len(pay.Trailer)))
+		}
+	}
+}
+
+func serverMetadataSentKeysLabels(ctx context.Context, stat stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	// keep alphabetical order
+	return []string{
+		tag.clientUserAgent,
+		metadataKind(stat),
+		tag.method,
+		tag.service,
+	}
+}
@@ -0,0 +1,68 @@
+package promgrpc_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+)
+
+func TestNewServerMetadataSentKeysStatsHandler(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.NewStatsHandler(promgrpc.NewServerMetadataSentKeysStatsHandler(promgrpc.NewServerMetadataSentKeysHistogramVec()))
+	ctx = metadata.NewIncomingContext(ctx, metadata.MD{"user-agent": []string{"fake-user-agent"}})
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+		FullMethodName: "/service/Method",
+		FailFast:       true,
+	})
+	h.HandleRPC(ctx, &stats.OutHeader{
+		Header: metadata.MD{"x-a": []string{"b"}},
+	})
+	h.HandleRPC(ctx, &stats.OutTrailer{
+		Trailer: metadata.MD{
+			"x-a": []string{"b"},
+			"x-b": []string{"c"},
+			"x-c": []string{"d"},
+		},
+	})
+
+	const metadata = `
+		# HELP grpc_server_metadata_sent_keys_histogram Number of metadata keys sent within headers and trailers.
+        # TYPE grpc_server_metadata_sent_keys_histogram histogram
+	`
+	expected := `
+		grpc_server_metadata_sent_keys_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service",le="1"} 1
+        grpc_server_metadata_sent_keys_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service",le="2"} 1
+        grpc_server_metadata_sent_keys_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service",le="4"} 1
+        grpc_server_metadata_sent_keys_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service",le="8"} 1
+        grpc_server_metadata_sent_keys_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service",le="16"} 1
+        grpc_server_metadata_sent_keys_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service",le="32"} 1
+        grpc_server_metadata_sent_keys_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service",le="64"} 1
+        grpc_server_metadata_sent_keys_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service",le="128"} 1
+        grpc_server_metadata_sent_keys_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service",le="+Inf"} 1
+        grpc_server_metadata_sent_keys_histogram_sum{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service"} 1
+        grpc_server_metadata_sent_keys_histogram_count{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="header",grpc_method="Method",grpc_service="service"} 1
+        grpc_server_metadata_sent_keys_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="trailer",grpc_method="Method",grpc_service="service",le="1"} 0
+        grpc_server_metadata_sent_keys_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="trailer",grpc_method="Method",grpc_service="service",le="2"} 0
+        grpc_server_metadata_sent_keys_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="trailer",grpc_method="Method",grpc_service="service",le="4"} 1
+        grpc_server_metadata_sent_keys_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="trailer",grpc_method="Method",grpc_service="service",le="8"} 1
+        grpc_server_metadata_sent_keys_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="trailer",grpc_method="Method",grpc_service="service",le="16"} 1
+        grpc_server_metadata_sent_keys_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="trailer",grpc_method="Method",grpc_service="service",le="32"} 1
+        grpc_server_metadata_sent_keys_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="trailer",grpc_method="Method",grpc_service="service",le="64"} 1
+        grpc_server_metadata_sent_keys_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="trailer",grpc_method="Method",grpc_service="service",le="128"} 1
+        grpc_server_metadata_sent_keys_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="trailer",grpc_method="Method",grpc_service="service",le="+Inf"} 1
+        grpc_server_metadata_sent_keys_histogram_sum{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="trailer",grpc_method="Method",grpc_service="service"} 3
+        grpc_server_metadata_sent_keys_histogram_count{grpc_client_user_agent="fake-user-agent",grpc_metadata_kind="trailer",grpc_method="Method",grpc_service="service"} 1
+	`
+
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_server_metadata_sent_keys_histogram"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,69 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewServerMetadataSentSizeHistogramVec allocates a new Prometheus HistogramVec for the server and given set of options.
+func NewServerMetadataSentSizeHistogramVec(opts ...CollectorOption) *prometheus.HistogramVec {
+	labels := []string{
+		// keep alphabetical order
+		labelClientUserAgent,
+		labelMetadataKind,
+		labelMethod,
+		labelService,
+	}
+	return newMetadataSentSizeHistogramVec("server", labels, opts...)
+}
+
+// ServerMetadataSentSizeStatsHandler is responsible for observing the size of outgoing response headers and trailers.
+// The transport does not report their wire length, hence the size is estimated the same way HTTP/2 computes a header list size.
+// It is the size that SETTINGS_MAX_HEADER_LIST_SIZE limit applies to.
+type ServerMetadataSentSizeStatsHandler struct {
+	baseStatsHandler
+	vec prometheus.ObserverVec
+}
+
+// NewServerMetadataSentSizeStatsHandler ...
+func NewServerMetadataSentSizeStatsHandler(vec prometheus.ObserverVec, opts ...StatsHandlerOption) *ServerMetadataSentSizeStatsHandler {
+	h := &ServerMetadataSentSizeStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+			options: statsHandlerOptions{
+				handleRPCLabelFn: serverMetadataSentSizeLabels,
+			},
+		},
+		vec: vec,
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ServerMetadataSentSizeStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	switch pay := stat.(type) {
+	case *stats.OutHeader:
+		if !stat.IsClient() {
+			h.observe(ctx, h.vec, h.labelValues(ctx, stat), float64(metadataSize(pay.Header)))
+		}
+	case *stats.OutTrailer:
+		if !stat.IsClient() {
+			h.observe(ctx, h.vec, h.labelValues(ctx, stat), float64(metadataSize(pay.Trailer)))
+		}
+	}
+}
+
+func serverMetadataSentSizeLabels(ctx context.Context, stat stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	// keep alphabetical order
+	return []string{
+		tag.clientUserAgent,
+		metadataKind(stat),
+		tag.method,
+		tag.service,
+	}
+}
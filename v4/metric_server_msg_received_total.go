@@ -0,0 +1,66 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewServerMsgReceivedCounterVec allocates a new Prometheus CounterVec for the server and given set of options.
+// It is compatible with go-grpc-prometheus.
+func NewServerMsgReceivedCounterVec(opts ...CollectorOption) *prometheus.CounterVec {
+	labels := []string{
+		// keep alphabetical order
+		labelMethod,
+		labelService,
+		labelType,
+	}
+	return newMsgReceivedTotalCounterVec("server", "Total number of RPC stream messages received on the server.", labels, opts...)
+}
+
+// ServerMsgReceivedStatsHandler is responsible for counting messages received by the server, the same way go-grpc-prometheus does (grpc_server_msg_received_total).
+type ServerMsgReceivedStatsHandler struct {
+	baseStatsHandler
+	vec *prometheus.CounterVec
+}
+
+// NewServerMsgReceivedStatsHandler ...
+func NewServerMsgReceivedStatsHandler(vec *prometheus.CounterVec, opts ...StatsHandlerOption) *ServerMsgReceivedStatsHandler {
+	h := &ServerMsgReceivedStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+			options: statsHandlerOptions{
+				handleRPCLabelFn: serverMsgReceivedLabels,
+			},
+		},
+		vec: vec,
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ServerMsgReceivedStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if _, ok := stat.(*stats.InPayload); ok && !stat.IsClient() {
+		h.inc(ctx, h.vec.WithLabelValues(h.labelValues(ctx, stat)...))
+	}
+}
+
+// Preinitialize creates series of every method a server exposes, see StatsHandler.Preinitialize.
+func (h *ServerMsgReceivedStatsHandler) Preinitialize(provider ServiceInfoProvider) {
+	for _, lvs := range h.preinitializedLabels(provider, &stats.InPayload{}) {
+		h.vec.WithLabelValues(lvs...)
+	}
+}
+
+func serverMsgReceivedLabels(ctx context.Context, _ stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	// keep alphabetical order
+	return []string{
+		tag.method,
+		tag.service,
+		tag.rpcType,
+	}
+}
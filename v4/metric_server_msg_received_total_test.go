@@ -0,0 +1,44 @@
+package promgrpc_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/stats"
+)
+
+func TestNewServerMsgReceivedStatsHandler(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.NewStatsHandler(promgrpc.NewServerMsgReceivedStatsHandler(promgrpc.NewServerMsgReceivedCounterVec()))
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+		FullMethodName: "/service/Method",
+		FailFast:       true,
+	})
+	h.HandleRPC(ctx, &stats.Begin{
+		IsClientStream: true,
+		IsServerStream: true,
+	})
+	h.HandleRPC(ctx, &stats.InPayload{})
+	h.HandleRPC(ctx, &stats.InPayload{})
+	h.HandleRPC(ctx, &stats.InPayload{
+		Client: true,
+	})
+
+	const metadata = `
+		# HELP grpc_server_msg_received_total Total number of RPC stream messages received on the server.
+        # TYPE grpc_server_msg_received_total counter
+	`
+	expected := `
+		grpc_server_msg_received_total{grpc_method="Method",grpc_service="service",grpc_type="bidi_stream"} 2
+	`
+
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_server_msg_received_total"); err != nil {
+		t.Fatal(err)
+	}
+}
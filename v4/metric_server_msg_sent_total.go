@@ -0,0 +1,66 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewServerMsgSentCounterVec allocates a new Prometheus CounterVec for the server and given set of options.
+// It is compatible with go-grpc-prometheus.
+func NewServerMsgSentCounterVec(opts ...CollectorOption) *prometheus.CounterVec {
+	labels := []string{
+		// keep alphabetical order
+		labelMethod,
+		labelService,
+		labelType,
+	}
+	return newMsgSentTotalCounterVec("server", "Total number of gRPC stream messages sent by the server.", labels, opts...)
+}
+
+// ServerMsgSentStatsHandler is responsible for counting messages sent by the server, the same way go-grpc-prometheus does (grpc_server_msg_sent_total).
+type ServerMsgSentStatsHandler struct {
+	baseStatsHandler
+	vec *prometheus.CounterVec
+}
+
+// NewServerMsgSentStatsHandler ...
+func NewServerMsgSentStatsHandler(vec *prometheus.CounterVec, opts ...StatsHandlerOption) *ServerMsgSentStatsHandler {
+	h := &ServerMsgSentStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+			options: statsHandlerOptions{
+				handleRPCLabelFn: serverMsgSentLabels,
+			},
+		},
+		vec: vec,
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ServerMsgSentStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if _, ok := stat.(*stats.OutPayload); ok && !stat.IsClient() {
+		h.inc(ctx, h.vec.WithLabelValues(h.labelValues(ctx, stat)...))
+	}
+}
+
+// Preinitialize creates series of every method a server exposes, see StatsHandler.Preinitialize.
+func (h *ServerMsgSentStatsHandler) Preinitialize(provider ServiceInfoProvider) {
+	for _, lvs := range h.preinitializedLabels(provider, &stats.OutPayload{}) {
+		h.vec.WithLabelValues(lvs...)
+	}
+}
+
+func serverMsgSentLabels(ctx context.Context, _ stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	// keep alphabetical order
+	return []string{
+		tag.method,
+		tag.service,
+		tag.rpcType,
+	}
+}
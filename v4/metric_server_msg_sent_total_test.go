@@ -0,0 +1,44 @@
+package promgrpc_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/stats"
+)
+
+func TestNewServerMsgSentStatsHandler(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.NewStatsHandler(promgrpc.NewServerMsgSentStatsHandler(promgrpc.NewServerMsgSentCounterVec()))
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+		FullMethodName: "/service/Method",
+		FailFast:       true,
+	})
+	h.HandleRPC(ctx, &stats.Begin{
+		IsClientStream: true,
+		IsServerStream: true,
+	})
+	h.HandleRPC(ctx, &stats.OutPayload{})
+	h.HandleRPC(ctx, &stats.OutPayload{})
+	h.HandleRPC(ctx, &stats.OutPayload{
+		Client: true,
+	})
+
+	const metadata = `
+		# HELP grpc_server_msg_sent_total Total number of gRPC stream messages sent by the server.
+        # TYPE grpc_server_msg_sent_total counter
+	`
+	expected := `
+		grpc_server_msg_sent_total{grpc_method="Method",grpc_service="service",grpc_type="bidi_stream"} 2
+	`
+
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_server_msg_sent_total"); err != nil {
+		t.Fatal(err)
+	}
+}
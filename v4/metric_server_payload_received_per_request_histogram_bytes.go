@@ -0,0 +1,81 @@
+package promgrpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc/status"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewServerPayloadReceivedPerRequestHistogramVec allocates a new Prometheus HistogramVec for the server and given set of options.
+func NewServerPayloadReceivedPerRequestHistogramVec(opts ...CollectorOption) *prometheus.HistogramVec {
+	labels := []string{
+		labelClientUserAgent,
+		labelCode,
+		labelMethod,
+		labelService,
+	}
+	return newPayloadReceivedPerRequestHistogramVec("server", labels, opts...)
+}
+
+// ServerPayloadReceivedPerRequestStatsHandler is responsible for observing the total size of messages received within a single RPC.
+// The total is observed once the RPC ends.
+type ServerPayloadReceivedPerRequestStatsHandler struct {
+	baseStatsHandler
+	vec prometheus.ObserverVec
+}
+
+// NewServerPayloadReceivedPerRequestStatsHandler ...
+func NewServerPayloadReceivedPerRequestStatsHandler(vec prometheus.ObserverVec, opts ...StatsHandlerOption) *ServerPayloadReceivedPerRequestStatsHandler {
+	h := &ServerPayloadReceivedPerRequestStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+			options: statsHandlerOptions{
+				handleRPCLabelFn: serverPayloadReceivedPerRequestLabels,
+			},
+		},
+		vec: vec,
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// TagRPC implements stats Handler interface.
+func (h *ServerPayloadReceivedPerRequestStatsHandler) TagRPC(ctx context.Context, inf *stats.RPCTagInfo) context.Context {
+	ctx = h.baseStatsHandler.TagRPC(ctx, inf)
+	ctx = context.WithValue(ctx, serverPayloadReceivedPerRequestKey{}, &requestTotalMark{})
+	return ctx
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ServerPayloadReceivedPerRequestStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if stat.IsClient() {
+		return
+	}
+
+	switch pay := stat.(type) {
+	case *stats.InPayload:
+		if mrk, ok := ctx.Value(serverPayloadReceivedPerRequestKey{}).(*requestTotalMark); ok {
+			mrk.add(int64(pay.Length))
+		}
+	case *stats.End:
+		if mrk, ok := ctx.Value(serverPayloadReceivedPerRequestKey{}).(*requestTotalMark); ok {
+			h.observe(ctx, h.vec, h.labelValues(ctx, stat), float64(mrk.load()))
+		}
+	}
+}
+
+func serverPayloadReceivedPerRequestLabels(ctx context.Context, stat stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	return []string{
+		tag.clientUserAgent,
+		status.Code(stat.(*stats.End).Error).String(),
+		tag.method,
+		tag.service,
+	}
+}
+
+type serverPayloadReceivedPerRequestKey struct{}
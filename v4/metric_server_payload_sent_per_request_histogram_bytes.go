@@ -0,0 +1,81 @@
+package promgrpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc/status"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewServerPayloadSentPerRequestHistogramVec allocates a new Prometheus HistogramVec for the server and given set of options.
+func NewServerPayloadSentPerRequestHistogramVec(opts ...CollectorOption) *prometheus.HistogramVec {
+	labels := []string{
+		labelClientUserAgent,
+		labelCode,
+		labelMethod,
+		labelService,
+	}
+	return newPayloadSentPerRequestHistogramVec("server", labels, opts...)
+}
+
+// ServerPayloadSentPerRequestStatsHandler is responsible for observing the total size of messages sent within a single RPC.
+// The total is observed once the RPC ends.
+type ServerPayloadSentPerRequestStatsHandler struct {
+	baseStatsHandler
+	vec prometheus.ObserverVec
+}
+
+// NewServerPayloadSentPerRequestStatsHandler ...
+func NewServerPayloadSentPerRequestStatsHandler(vec prometheus.ObserverVec, opts ...StatsHandlerOption) *ServerPayloadSentPerRequestStatsHandler {
+	h := &ServerPayloadSentPerRequestStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+			options: statsHandlerOptions{
+				handleRPCLabelFn: serverPayloadSentPerRequestLabels,
+			},
+		},
+		vec: vec,
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// TagRPC implements stats Handler interface.
+func (h *ServerPayloadSentPerRequestStatsHandler) TagRPC(ctx context.Context, inf *stats.RPCTagInfo) context.Context {
+	ctx = h.baseStatsHandler.TagRPC(ctx, inf)
+	ctx = context.WithValue(ctx, serverPayloadSentPerRequestKey{}, &requestTotalMark{})
+	return ctx
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ServerPayloadSentPerRequestStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if stat.IsClient() {
+		return
+	}
+
+	switch pay := stat.(type) {
+	case *stats.OutPayload:
+		if mrk, ok := ctx.Value(serverPayloadSentPerRequestKey{}).(*requestTotalMark); ok {
+			mrk.add(int64(pay.Length))
+		}
+	case *stats.End:
+		if mrk, ok := ctx.Value(serverPayloadSentPerRequestKey{}).(*requestTotalMark); ok {
+			h.observe(ctx, h.vec, h.labelValues(ctx, stat), float64(mrk.load()))
+		}
+	}
+}
+
+func serverPayloadSentPerRequestLabels(ctx context.Context, stat stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	return []string{
+		tag.clientUserAgent,
+		status.Code(stat.(*stats.End).Error).String(),
+		tag.method,
+		tag.service,
+	}
+}
+
+type serverPayloadSentPerRequestKey struct{}
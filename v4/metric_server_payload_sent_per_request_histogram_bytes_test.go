@@ -0,0 +1,65 @@
+package promgrpc_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+)
+
+func TestNewServerPayloadSentPerRequestStatsHandler(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.NewStatsHandler(promgrpc.NewServerPayloadSentPerRequestStatsHandler(promgrpc.NewServerPayloadSentPerRequestHistogramVec()))
+	ctx = metadata.NewIncomingContext(ctx, metadata.MD{"user-agent": []string{"fake-user-agent"}})
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+		FullMethodName: "/service/Method",
+		FailFast:       true,
+	})
+	h.HandleRPC(ctx, &stats.OutPayload{
+		Length: 100,
+	})
+	h.HandleRPC(ctx, &stats.OutPayload{
+		Length: 200,
+	})
+	h.HandleRPC(ctx, &stats.OutPayload{
+		Length: 300,
+	})
+	h.HandleRPC(ctx, &stats.InPayload{
+		Length: 1000,
+	})
+	h.HandleRPC(ctx, &stats.End{})
+	h.HandleRPC(ctx, &stats.End{
+		Client: true,
+	})
+
+	const metadata = `
+		# HELP grpc_server_payload_sent_per_request_histogram_bytes Total size of messages sent within a single RPC.
+        # TYPE grpc_server_payload_sent_per_request_histogram_bytes histogram
+	`
+	expected := `
+		grpc_server_payload_sent_per_request_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_method="Method",grpc_service="service",le="64"} 0
+        grpc_server_payload_sent_per_request_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_method="Method",grpc_service="service",le="256"} 0
+        grpc_server_payload_sent_per_request_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_method="Method",grpc_service="service",le="1024"} 1
+        grpc_server_payload_sent_per_request_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_method="Method",grpc_service="service",le="4096"} 1
+        grpc_server_payload_sent_per_request_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_method="Method",grpc_service="service",le="16384"} 1
+        grpc_server_payload_sent_per_request_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_method="Method",grpc_service="service",le="65536"} 1
+        grpc_server_payload_sent_per_request_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_method="Method",grpc_service="service",le="262144"} 1
+        grpc_server_payload_sent_per_request_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_method="Method",grpc_service="service",le="1048576"} 1
+        grpc_server_payload_sent_per_request_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_method="Method",grpc_service="service",le="4194304"} 1
+        grpc_server_payload_sent_per_request_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_method="Method",grpc_service="service",le="16777216"} 1
+        grpc_server_payload_sent_per_request_histogram_bytes_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_method="Method",grpc_service="service",le="+Inf"} 1
+        grpc_server_payload_sent_per_request_histogram_bytes_sum{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_method="Method",grpc_service="service"} 600
+        grpc_server_payload_sent_per_request_histogram_bytes_count{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_method="Method",grpc_service="service"} 1
+	`
+
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_server_payload_sent_per_request_histogram_bytes"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,77 @@
+package promgrpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewServerRequestDeadlineBudgetHistogramVec allocates a new Prometheus HistogramVec for the server and given set of options.
+func NewServerRequestDeadlineBudgetHistogramVec(opts ...CollectorOption) *prometheus.HistogramVec {
+	labels := []string{
+		labelClientUserAgent,
+		labelMethod,
+		labelService,
+	}
+	return newRequestDeadlineBudgetHistogramVec("server", labels, opts...)
+}
+
+// ServerRequestDeadlineBudgetStatsHandler is responsible for observing how much time an incoming RPC has left until its deadline when it begins.
+// RPCs without a deadline are not observed, see ServerRequestsWithoutDeadlineTotalStatsHandler.
+// RPCs that arrive already past their deadline are observed as zero.
+type ServerRequestDeadlineBudgetStatsHandler struct {
+	baseStatsHandler
+	vec prometheus.ObserverVec
+}
+
+// NewServerRequestDeadlineBudgetStatsHandler ...
+func NewServerRequestDeadlineBudgetStatsHandler(vec prometheus.ObserverVec, opts ...StatsHandlerOption) *ServerRequestDeadlineBudgetStatsHandler {
+	h := &ServerRequestDeadlineBudgetStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+			options: statsHandlerOptions{
+				handleRPCLabelFn: serverRequestDeadlineBudgetLabels,
+			},
+		},
+		vec: vec,
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ServerRequestDeadlineBudgetStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if pay, ok := stat.(*stats.Begin); ok && !stat.IsClient() {
+		if budget, ok := remaining(ctx, pay.BeginTime); ok {
+			h.observe(ctx, h.vec, h.labelValues(ctx, stat), budget.Seconds())
+		}
+	}
+}
+
+func serverRequestDeadlineBudgetLabels(ctx context.Context, _ stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	return []string{
+		tag.clientUserAgent,
+		tag.method,
+		tag.service,
+	}
+}
+
+// remaining returns time left until the deadline of a given context, measured from a given point in time.
+// It reports false if the context has no deadline.
+func remaining(ctx context.Context, t time.Time) (time.Duration, bool) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return 0, false
+	}
+	if t.IsZero() {
+		t = time.Now()
+	}
+	if left := deadline.Sub(t); left > 0 {
+		return left, true
+	}
+	return 0, true
+}
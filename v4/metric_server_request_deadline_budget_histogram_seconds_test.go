@@ -0,0 +1,63 @@
+package promgrpc_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+)
+
+func TestNewServerRequestDeadlineBudgetStatsHandler(t *testing.T) {
+	deadline := time.Now().Add(time.Minute)
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	h := promgrpc.NewStatsHandler(promgrpc.NewServerRequestDeadlineBudgetStatsHandler(promgrpc.NewServerRequestDeadlineBudgetHistogramVec()))
+	ctx = metadata.NewIncomingContext(ctx, metadata.MD{"user-agent": []string{"fake-user-agent"}})
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+		FullMethodName: "/service/Method",
+	})
+	h.HandleRPC(ctx, &stats.Begin{
+		BeginTime: deadline.Add(-2 * time.Second),
+	})
+	h.HandleRPC(ctx, &stats.Begin{
+		BeginTime: deadline.Add(time.Second),
+	})
+	h.HandleRPC(ctx, &stats.Begin{
+		Client:    true,
+		BeginTime: deadline.Add(-2 * time.Second),
+	})
+
+	const metadata = `
+		# HELP grpc_server_request_deadline_budget_histogram_seconds Time left until the deadline of an RPC when it began.
+        # TYPE grpc_server_request_deadline_budget_histogram_seconds histogram
+	`
+	expected := `
+		grpc_server_request_deadline_budget_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_service="service",le="0.005"} 1
+        grpc_server_request_deadline_budget_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_service="service",le="0.01"} 1
+        grpc_server_request_deadline_budget_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_service="service",le="0.025"} 1
+        grpc_server_request_deadline_budget_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_service="service",le="0.05"} 1
+        grpc_server_request_deadline_budget_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_service="service",le="0.1"} 1
+        grpc_server_request_deadline_budget_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_service="service",le="0.25"} 1
+        grpc_server_request_deadline_budget_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_service="service",le="0.5"} 1
+        grpc_server_request_deadline_budget_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_service="service",le="1"} 1
+        grpc_server_request_deadline_budget_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_service="service",le="2.5"} 2
+        grpc_server_request_deadline_budget_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_service="service",le="5"} 2
+        grpc_server_request_deadline_budget_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_service="service",le="10"} 2
+        grpc_server_request_deadline_budget_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_service="service",le="30"} 2
+        grpc_server_request_deadline_budget_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_service="service",le="60"} 2
+        grpc_server_request_deadline_budget_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_service="service",le="300"} 2
+        grpc_server_request_deadline_budget_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_service="service",le="+Inf"} 2
+        grpc_server_request_deadline_budget_histogram_seconds_sum{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_service="service"} 2
+        grpc_server_request_deadline_budget_histogram_seconds_count{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_service="service"} 2
+	`
+
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_server_request_deadline_budget_histogram_seconds"); err != nil {
+		t.Fatal(err)
+	}
+}
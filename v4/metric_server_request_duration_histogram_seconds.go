@@ -29,7 +29,8 @@ type ServerRequestDurationStatsHandler struct {
 func NewServerRequestDurationStatsHandler(vec prometheus.ObserverVec, opts ...StatsHandlerOption) *ServerRequestDurationStatsHandler {
 	h := &ServerRequestDurationStatsHandler{
 		baseStatsHandler: baseStatsHandler{
-			collector: vec,
+			collector:       vec,
+			supportedLabels: optionalLabelType,
 			options: statsHandlerOptions{
 				handleRPCLabelFn: serverRequestDurationLabels,
 			},
@@ -46,15 +47,13 @@ func (h *ServerRequestDurationStatsHandler) HandleRPC(ctx context.Context, stat
 	if end, ok := stat.(*stats.End); ok {
 		switch {
 		case !stat.IsClient():
-			h.vec.
-				WithLabelValues(h.options.handleRPCLabelFn(ctx, stat)...).
-				Observe(end.EndTime.Sub(end.BeginTime).Seconds())
+			h.observe(ctx, h.vec, h.labelValues(ctx, stat), end.EndTime.Sub(end.BeginTime).Seconds())
 		}
 	}
 }
 
 func serverRequestDurationLabels(ctx context.Context, stat stats.RPCStats) []string {
-	tag := ctx.Value(tagRPCKey).(rpcTagLabels)
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
 	return []string{
 		tag.clientUserAgent,
 		status.Code(stat.(*stats.End).Error).String(),
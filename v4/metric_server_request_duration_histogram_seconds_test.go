@@ -9,6 +9,7 @@ import (
 	"google.golang.org/grpc/metadata"
 
 	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/testutil"
 	"google.golang.org/grpc/stats"
 )
@@ -68,3 +69,117 @@ func TestNewServerRequestDurationStatsHandler(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestNewServerRequestDurationStatsHandler_exemplars(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.ServerStatsHandler(promgrpc.StatsHandlerWithExemplars(func(ctx context.Context) prometheus.Labels {
+		if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get("x-request-id")) > 0 {
+			return prometheus.Labels{"request_id": md.Get("x-request-id")[0]}
+		}
+		return nil
+	}))
+	reg := prometheus.NewRegistry()
+	registerCollector(t, reg, h)
+
+	bt := time.Now()
+	ctx = metadata.NewIncomingContext(ctx, metadata.MD{"x-request-id": []string{"abc"}})
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+		FullMethodName: "/service/Method",
+	})
+	h.HandleRPC(ctx, &stats.Begin{
+		BeginTime: bt,
+	})
+	h.HandleRPC(ctx, &stats.End{
+		BeginTime: bt,
+		EndTime:   bt.Add(200 * time.Millisecond),
+	})
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var histogram, counter bool
+	for _, family := range families {
+		switch family.GetName() {
+		case "grpc_server_request_duration_histogram_seconds":
+			for _, bucket := range family.GetMetric()[0].GetHistogram().GetBucket() {
+				if ex := bucket.GetExemplar(); ex != nil {
+					histogram = true
+					if ex.GetValue() != 0.2 || ex.GetLabel()[0].GetValue() != "abc" {
+						t.Errorf("unexpected exemplar: %v", ex)
+					}
+				}
+			}
+		case "grpc_server_responses_sent_total":
+			if ex := family.GetMetric()[0].GetCounter().GetExemplar(); ex != nil {
+				counter = true
+				if ex.GetLabel()[0].GetValue() != "abc" {
+					t.Errorf("unexpected exemplar: %v", ex)
+				}
+			}
+		}
+	}
+	if !histogram {
+		t.Error("histogram exemplar not found")
+	}
+	if !counter {
+		t.Error("counter exemplar not found")
+	}
+}
+
+func TestNewServerRequestDurationStatsHandler_methodVecs(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	vecs := promgrpc.NewMethodHistogramVecs(promgrpc.NewServerRequestDurationHistogramVec, map[string][]float64{
+		"/service/Batch":  {60, 600},
+		"/service/Import": {60, 600},
+	})
+	h := promgrpc.NewStatsHandler(promgrpc.NewServerRequestDurationStatsHandler(
+		promgrpc.NewServerRequestDurationHistogramVec(promgrpc.CollectorWithBuckets(1)),
+		promgrpc.StatsHandlerWithMethodVecs(vecs),
+	))
+	reg := prometheus.NewRegistry()
+	registerCollector(t, reg, h)
+
+	bt := time.Now()
+	ctx = metadata.NewIncomingContext(ctx, metadata.MD{"user-agent": []string{"fake-user-agent"}})
+	for method, took := range map[string]time.Duration{
+		"/service/Method": 500 * time.Millisecond,
+		"/service/Batch":  2 * time.Minute,
+		"/service/Import": 30 * time.Second,
+	} {
+		h.HandleRPC(h.TagRPC(ctx, &stats.RPCTagInfo{FullMethodName: method}), &stats.End{
+			BeginTime: bt,
+			EndTime:   bt.Add(took),
+		})
+	}
+
+	const metadata = `
+		# HELP grpc_server_request_duration_histogram_seconds TODO
+        # TYPE grpc_server_request_duration_histogram_seconds histogram
+	`
+	expected := `
+		grpc_server_request_duration_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_method="Batch",grpc_service="service",le="60"} 0
+		grpc_server_request_duration_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_method="Batch",grpc_service="service",le="600"} 1
+		grpc_server_request_duration_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_method="Batch",grpc_service="service",le="+Inf"} 1
+		grpc_server_request_duration_histogram_seconds_sum{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_method="Batch",grpc_service="service"} 120
+		grpc_server_request_duration_histogram_seconds_count{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_method="Batch",grpc_service="service"} 1
+		grpc_server_request_duration_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_method="Import",grpc_service="service",le="60"} 1
+		grpc_server_request_duration_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_method="Import",grpc_service="service",le="600"} 1
+		grpc_server_request_duration_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_method="Import",grpc_service="service",le="+Inf"} 1
+		grpc_server_request_duration_histogram_seconds_sum{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_method="Import",grpc_service="service"} 30
+		grpc_server_request_duration_histogram_seconds_count{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_method="Import",grpc_service="service"} 1
+		grpc_server_request_duration_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_method="Method",grpc_service="service",le="1"} 1
+		grpc_server_request_duration_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_method="Method",grpc_service="service",le="+Inf"} 1
+		grpc_server_request_duration_histogram_seconds_sum{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_method="Method",grpc_service="service"} 0.5
+		grpc_server_request_duration_histogram_seconds_count{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_method="Method",grpc_service="service"} 1
+	`
+
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(metadata+expected), "grpc_server_request_duration_histogram_seconds"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,14 @@
+package promgrpc
+
+// NewServerRequestDurationSummaryVec allocates a new SketchSummaryVec for the server and given set of options.
+// It is an alternative to NewServerRequestDurationHistogramVec, meant to be passed to NewServerRequestDurationStatsHandler.
+func NewServerRequestDurationSummaryVec(opts ...CollectorOption) *SketchSummaryVec {
+	labels := []string{
+		labelClientUserAgent,
+		labelCode,
+		labelMethod,
+		labelService,
+	}
+
+	return newRequestDurationSummaryVec("server", labels, opts...)
+}
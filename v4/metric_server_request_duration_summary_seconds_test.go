@@ -0,0 +1,60 @@
+package promgrpc_test
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/piotrkowalczuk/promgrpc/v4/internal/testutil"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+)
+
+func TestNewServerRequestDurationSummaryVec(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.NewStatsHandler(promgrpc.NewServerRequestDurationStatsHandler(
+		promgrpc.NewServerRequestDurationSummaryVec(),
+	))
+	reg := prometheus.NewRegistry()
+	registerCollector(t, reg, h)
+
+	bt := time.Now()
+	ctx = metadata.NewIncomingContext(ctx, metadata.MD{"user-agent": []string{"fake-user-agent"}})
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+		FullMethodName: "/service/Method",
+	})
+	for i := 1; i <= 1000; i++ {
+		h.HandleRPC(ctx, &stats.End{
+			BeginTime: bt,
+			EndTime:   bt.Add(time.Duration(i) * time.Millisecond),
+		})
+	}
+
+	testutil.AssertMetricValue(t, reg, "grpc_server_request_duration_summary_seconds_count", 1000)
+	testutil.AssertMetricDimensions(t, reg, "grpc_server_request_duration_summary_seconds", map[string]string{
+		"grpc_client_user_agent": "fake-user-agent",
+		"grpc_code":              "OK",
+		"grpc_method":            "Method",
+		"grpc_service":           "service",
+	})
+
+	mf, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	quantiles := mf[0].GetMetric()[0].GetSummary().GetQuantile()
+	if len(quantiles) != 4 {
+		t.Fatalf("expected 4 quantiles, but got %d", len(quantiles))
+	}
+	for _, q := range quantiles {
+		exact := math.Floor(q.GetQuantile()*999+1) / 1000
+		if got := q.GetValue(); math.Abs(got-exact) > exact*promgrpc.DefSketchRelativeAccuracy {
+			t.Errorf("quantile %g: expected %g ±1%%, but got %g", q.GetQuantile(), exact, got)
+		}
+	}
+}
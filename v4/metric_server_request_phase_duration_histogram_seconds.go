@@ -0,0 +1,166 @@
+package promgrpc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewServerRequestPhaseDurationHistogramVec allocates a new Prometheus HistogramVec for the server and given set of options.
+func NewServerRequestPhaseDurationHistogramVec(opts ...CollectorOption) *prometheus.HistogramVec {
+	labels := []string{
+		// keep alphabetical order
+		labelClientUserAgent,
+		labelMethod,
+		labelPhase,
+		labelService,
+	}
+	return newRequestPhaseDurationHistogramVec("server", labels, opts...)
+}
+
+// ServerRequestPhaseDurationStatsHandler is responsible for measuring how long it takes for an incoming RPC to reach each phase of its lifecycle.
+// The phases are, in order: first message received, first message sent and end (trailers sent or RPC finished).
+// Each of them is measured since the beginning of the RPC, the same way ClientRequestPhaseDurationStatsHandler does.
+// The time spent in between two phases is the difference of them, a streaming RPC can reach them in a different order though.
+// grpc-go reports incoming headers before the beginning of an RPC, hence they are not a phase on their own.
+// Each phase is observed once per RPC, as soon as it is reached.
+// It makes it possible to tell apart slow handlers from slow request decoding or slow streaming.
+type ServerRequestPhaseDurationStatsHandler struct {
+	baseStatsHandler
+	vec prometheus.ObserverVec
+}
+
+// NewServerRequestPhaseDurationStatsHandler ...
+func NewServerRequestPhaseDurationStatsHandler(vec prometheus.ObserverVec, opts ...StatsHandlerOption) *ServerRequestPhaseDurationStatsHandler {
+	h := &ServerRequestPhaseDurationStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+			options: statsHandlerOptions{
+				handleRPCLabelFn: serverRequestPhaseDurationLabels,
+			},
+		},
+		vec: vec,
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// TagRPC implements stats Handler interface.
+func (h *ServerRequestPhaseDurationStatsHandler) TagRPC(ctx context.Context, inf *stats.RPCTagInfo) context.Context {
+	ctx = h.baseStatsHandler.TagRPC(ctx, inf)
+	ctx = context.WithValue(ctx, serverRequestPhaseKey{}, &requestPhaseMark{})
+	return ctx
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ServerRequestPhaseDurationStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if stat.IsClient() {
+		return
+	}
+	mrk, ok := ctx.Value(serverRequestPhaseKey{}).(*requestPhaseMark)
+	if !ok {
+		return
+	}
+
+	var at time.Time
+	switch pay := stat.(type) {
+	case *stats.Begin:
+		mrk.begin(pay.BeginTime)
+		return
+	case *stats.OutTrailer:
+		at = time.Now()
+	case *stats.InPayload:
+		at = pay.RecvTime
+	case *stats.OutPayload:
+		at = pay.SentTime
+	case *stats.End:
+		at = pay.EndTime
+	default:
+		return
+	}
+
+	if elapsed, ok := mrk.reach(requestPhase(stat), at); ok {
+		h.observe(ctx, h.vec, h.labelValues(ctx, stat), elapsed.Seconds())
+	}
+}
+
+func serverRequestPhaseDurationLabels(ctx context.Context, stat stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	// keep alphabetical order
+	return []string{
+		tag.clientUserAgent,
+		tag.method,
+		requestPhase(stat),
+		tag.service,
+	}
+}
+
+// requestPhase maps RPC stats into a phase an RPC reached once given event occurred.
+func requestPhase(stat stats.RPCStats) string {
+	switch stat.(type) {
+	case *stats.InHeader:
+		return phaseInHeader
+	case *stats.InPayload:
+		return phaseFirstInPayload
+	case *stats.OutPayload:
+		return phaseFirstOutPayload
+	case *stats.OutTrailer, *stats.End:
+		return phaseEnd
+	default:
+		return notAvailable
+	}
+}
+
+type serverRequestPhaseKey struct{}
+
+// requestPhaseMark keeps track of phases an RPC already reached.
+// Incoming and outgoing payloads of a streaming RPC can be reported concurrently, hence the lock.
+type requestPhaseMark struct {
+	lock    sync.Mutex
+	start   time.Time
+	reached []string
+}
+
+// begin sets the beginning of an RPC.
+func (m *requestPhaseMark) begin(t time.Time) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.start.IsZero() || t.Before(m.start) {
+		m.start = t
+	}
+}
+
+// reach returns time elapsed since the beginning of an RPC, but only if the given phase is reached for the first time.
+// If the beginning is not known, or comes after the phase, there is nothing to measure.
+func (m *requestPhaseMark) reach(phase string, t time.Time) (time.Duration, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if !m.first(phase) || m.start.IsZero() {
+		return 0, false
+	}
+	if t.IsZero() {
+		t = time.Now()
+	}
+	if t.Before(m.start) {
+		return 0, false
+	}
+	return t.Sub(m.start), true
+}
+
+// first marks a given phase as reached and reports whether it is reached for the first time.
+// It has to be called with the lock held.
+func (m *requestPhaseMark) first(phase string) bool {
+	for _, p := range m.reached {
+		if p == phase {
+			return false
+		}
+	}
+	m.reached = append(m.reached, phase)
+	return true
+}
@@ -0,0 +1,102 @@
+package promgrpc_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+)
+
+func TestNewServerRequestPhaseDurationStatsHandler(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	bt := time.Now()
+
+	h := promgrpc.NewStatsHandler(promgrpc.NewServerRequestPhaseDurationStatsHandler(promgrpc.NewServerRequestPhaseDurationHistogramVec()))
+	ctx = metadata.NewIncomingContext(ctx, metadata.MD{"user-agent": []string{"fake-user-agent"}})
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+		FullMethodName: "/service/Method",
+	})
+	// grpc-go reports incoming headers before the beginning of an RPC.
+	h.HandleRPC(ctx, &stats.InHeader{})
+	h.HandleRPC(ctx, &stats.Begin{
+		BeginTime: bt,
+	})
+	h.HandleRPC(ctx, &stats.InPayload{
+		RecvTime: bt.Add(1 * time.Second),
+	})
+	h.HandleRPC(ctx, &stats.InPayload{
+		RecvTime: bt.Add(2 * time.Second),
+	})
+	h.HandleRPC(ctx, &stats.OutPayload{
+		SentTime: bt.Add(2 * time.Second),
+	})
+	h.HandleRPC(ctx, &stats.End{
+		BeginTime: bt,
+		EndTime:   bt.Add(4 * time.Second),
+	})
+	h.HandleRPC(ctx, &stats.End{
+		Client:    true,
+		BeginTime: bt,
+		EndTime:   bt.Add(1 * time.Second),
+	})
+
+	const metadata = `
+		# HELP grpc_server_request_phase_duration_histogram_seconds Time elapsed from the beginning of an RPC until it reaches a given phase.
+        # TYPE grpc_server_request_phase_duration_histogram_seconds histogram
+	`
+	expected := `
+		grpc_server_request_phase_duration_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_phase="end",grpc_service="service",le="0.005"} 0
+        grpc_server_request_phase_duration_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_phase="end",grpc_service="service",le="0.01"} 0
+        grpc_server_request_phase_duration_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_phase="end",grpc_service="service",le="0.025"} 0
+        grpc_server_request_phase_duration_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_phase="end",grpc_service="service",le="0.05"} 0
+        grpc_server_request_phase_duration_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_phase="end",grpc_service="service",le="0.1"} 0
+        grpc_server_request_phase_duration_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_phase="end",grpc_service="service",le="0.25"} 0
+        grpc_server_request_phase_duration_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_phase="end",grpc_service="service",le="0.5"} 0
+        grpc_server_request_phase_duration_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_phase="end",grpc_service="service",le="1"} 0
+        grpc_server_request_phase_duration_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_phase="end",grpc_service="service",le="2.5"} 0
+        grpc_server_request_phase_duration_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_phase="end",grpc_service="service",le="5"} 1
+        grpc_server_request_phase_duration_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_phase="end",grpc_service="service",le="10"} 1
+        grpc_server_request_phase_duration_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_phase="end",grpc_service="service",le="+Inf"} 1
+        grpc_server_request_phase_duration_histogram_seconds_sum{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_phase="end",grpc_service="service"} 4
+        grpc_server_request_phase_duration_histogram_seconds_count{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_phase="end",grpc_service="service"} 1
+		grpc_server_request_phase_duration_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_phase="first_in_payload",grpc_service="service",le="0.005"} 0
+        grpc_server_request_phase_duration_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_phase="first_in_payload",grpc_service="service",le="0.01"} 0
+        grpc_server_request_phase_duration_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_phase="first_in_payload",grpc_service="service",le="0.025"} 0
+        grpc_server_request_phase_duration_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_phase="first_in_payload",grpc_service="service",le="0.05"} 0
+        grpc_server_request_phase_duration_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_phase="first_in_payload",grpc_service="service",le="0.1"} 0
+        grpc_server_request_phase_duration_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_phase="first_in_payload",grpc_service="service",le="0.25"} 0
+        grpc_server_request_phase_duration_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_phase="first_in_payload",grpc_service="service",le="0.5"} 0
+        grpc_server_request_phase_duration_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_phase="first_in_payload",grpc_service="service",le="1"} 1
+        grpc_server_request_phase_duration_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_phase="first_in_payload",grpc_service="service",le="2.5"} 1
+        grpc_server_request_phase_duration_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_phase="first_in_payload",grpc_service="service",le="5"} 1
+        grpc_server_request_phase_duration_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_phase="first_in_payload",grpc_service="service",le="10"} 1
+        grpc_server_request_phase_duration_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_phase="first_in_payload",grpc_service="service",le="+Inf"} 1
+        grpc_server_request_phase_duration_histogram_seconds_sum{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_phase="first_in_payload",grpc_service="service"} 1
+        grpc_server_request_phase_duration_histogram_seconds_count{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_phase="first_in_payload",grpc_service="service"} 1
+		grpc_server_request_phase_duration_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_phase="first_out_payload",grpc_service="service",le="0.005"} 0
+        grpc_server_request_phase_duration_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_phase="first_out_payload",grpc_service="service",le="0.01"} 0
+        grpc_server_request_phase_duration_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_phase="first_out_payload",grpc_service="service",le="0.025"} 0
+        grpc_server_request_phase_duration_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_phase="first_out_payload",grpc_service="service",le="0.05"} 0
+        grpc_server_request_phase_duration_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_phase="first_out_payload",grpc_service="service",le="0.1"} 0
+        grpc_server_request_phase_duration_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_phase="first_out_payload",grpc_service="service",le="0.25"} 0
+        grpc_server_request_phase_duration_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_phase="first_out_payload",grpc_service="service",le="0.5"} 0
+        grpc_server_request_phase_duration_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_phase="first_out_payload",grpc_service="service",le="1"} 0
+        grpc_server_request_phase_duration_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_phase="first_out_payload",grpc_service="service",le="2.5"} 1
+        grpc_server_request_phase_duration_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_phase="first_out_payload",grpc_service="service",le="5"} 1
+        grpc_server_request_phase_duration_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_phase="first_out_payload",grpc_service="service",le="10"} 1
+        grpc_server_request_phase_duration_histogram_seconds_bucket{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_phase="first_out_payload",grpc_service="service",le="+Inf"} 1
+        grpc_server_request_phase_duration_histogram_seconds_sum{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_phase="first_out_payload",grpc_service="service"} 2
+        grpc_server_request_phase_duration_histogram_seconds_count{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_phase="first_out_payload",grpc_service="service"} 1
+	`
+
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_server_request_phase_duration_histogram_seconds"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -43,18 +43,25 @@ func (h *ServerRequestsInFlightStatsHandler) HandleRPC(ctx context.Context, stat
 	case *stats.Begin:
 		switch {
 		case !stat.IsClient():
-			h.vec.WithLabelValues(h.options.handleRPCLabelFn(ctx, stat)...).Inc()
+			h.vec.WithLabelValues(h.labelValues(ctx, stat)...).Inc()
 		}
 	case *stats.End:
 		switch {
 		case !stat.IsClient():
-			h.vec.WithLabelValues(h.options.handleRPCLabelFn(ctx, stat)...).Dec()
+			h.vec.WithLabelValues(h.labelValues(ctx, stat)...).Dec()
 		}
 	}
 }
 
+// Preinitialize creates series of every method a server exposes, see StatsHandler.Preinitialize.
+func (h *ServerRequestsInFlightStatsHandler) Preinitialize(provider ServiceInfoProvider) {
+	for _, lvs := range h.preinitializedLabels(provider, &stats.Begin{}) {
+		h.vec.WithLabelValues(lvs...)
+	}
+}
+
 func serverRequestsInFlightLabels(ctx context.Context, _ stats.RPCStats) []string {
-	tag := ctx.Value(tagRPCKey).(rpcTagLabels)
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
 	// keep alphabetical order
 	return []string{
 		tag.method,
@@ -0,0 +1,69 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewServerRequestsPerConnectionHistogramVec allocates a new Prometheus HistogramVec for the server and given set of options.
+func NewServerRequestsPerConnectionHistogramVec(opts ...CollectorOption) *prometheus.HistogramVec {
+	labels := []string{labelLocalAddr, labelClientUserAgent}
+	return newRequestsPerConnectionHistogramVec("server", labels, opts...)
+}
+
+// ServerRequestsPerConnectionStatsHandler is responsible for observing the number of RPCs an incoming connection carried during its lifetime.
+// It is observed once the connection ends.
+// Connections that carry a single RPC point at clients that open a connection per call.
+type ServerRequestsPerConnectionStatsHandler struct {
+	baseStatsHandler
+	vec prometheus.ObserverVec
+}
+
+// NewServerRequestsPerConnectionStatsHandler ...
+func NewServerRequestsPerConnectionStatsHandler(vec prometheus.ObserverVec) *ServerRequestsPerConnectionStatsHandler {
+	return &ServerRequestsPerConnectionStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+		},
+		vec: vec,
+	}
+}
+
+// TagConn implements stats Handler interface.
+func (h *ServerRequestsPerConnectionStatsHandler) TagConn(ctx context.Context, inf *stats.ConnTagInfo) context.Context {
+	ctx = h.baseStatsHandler.TagConn(ctx, inf)
+	ctx = context.WithValue(ctx, serverRequestsPerConnectionKey{}, &connectionRequests{labels: h.labels(ctx)})
+	return ctx
+}
+
+// HandleConn implements stats Handler interface.
+func (h *ServerRequestsPerConnectionStatsHandler) HandleConn(ctx context.Context, stat stats.ConnStats) {
+	if _, ok := stat.(*stats.ConnEnd); ok && !stat.IsClient() {
+		if conn, ok := ctx.Value(serverRequestsPerConnectionKey{}).(*connectionRequests); ok {
+			h.vec.WithLabelValues(conn.labels...).Observe(float64(conn.total.Load()))
+		}
+	}
+}
+
+func (h *ServerRequestsPerConnectionStatsHandler) tracksConnections() {}
+
+// HandleRPC implements stats Handler interface.
+func (h *ServerRequestsPerConnectionStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if _, ok := stat.(*stats.Begin); ok && !stat.IsClient() {
+		if conn, ok := ctx.Value(serverRequestsPerConnectionKey{}).(*connectionRequests); ok {
+			conn.begin()
+		}
+	}
+}
+
+func (h *ServerRequestsPerConnectionStatsHandler) labels(ctx context.Context) []string {
+	tag := ctx.Value(tagConnKey).(connTagLabels)
+	return []string{
+		tag.localAddr,
+		tag.clientUserAgent,
+	}
+}
+
+type serverRequestsPerConnectionKey struct{}
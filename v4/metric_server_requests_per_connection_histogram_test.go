@@ -0,0 +1,65 @@
+package promgrpc_test
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+)
+
+func TestNewServerRequestsPerConnectionStatsHandler(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.NewStatsHandler(promgrpc.NewServerRequestsPerConnectionStatsHandler(promgrpc.NewServerRequestsPerConnectionHistogramVec()))
+	ctx = metadata.NewIncomingContext(ctx, metadata.MD{"user-agent": []string{"fake-user-agent"}})
+	ctx = h.TagConn(ctx, &stats.ConnTagInfo{
+		LocalAddr: &net.TCPAddr{
+			IP:   net.IPv4(1, 2, 3, 4),
+			Port: 80,
+		},
+		RemoteAddr: &net.TCPAddr{
+			IP:   net.IPv4(4, 3, 2, 1),
+			Port: 111,
+		},
+	})
+	h.HandleConn(ctx, &stats.ConnBegin{})
+	for i := 0; i < 3; i++ {
+		rpcCtx := h.TagRPC(ctx, &stats.RPCTagInfo{
+			FullMethodName: "/service/Method",
+		})
+		h.HandleRPC(rpcCtx, &stats.Begin{})
+		h.HandleRPC(rpcCtx, &stats.Begin{Client: true})
+		h.HandleRPC(rpcCtx, &stats.End{})
+	}
+	h.HandleConn(ctx, &stats.ConnEnd{})
+
+	const metadata = `
+		# HELP grpc_server_requests_per_connection_histogram Number of RPCs carried by a single connection during its lifetime.
+		# TYPE grpc_server_requests_per_connection_histogram histogram
+	`
+	expected := `
+		grpc_server_requests_per_connection_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_local_addr="1.2.3.4:80",le="1"} 0
+        grpc_server_requests_per_connection_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_local_addr="1.2.3.4:80",le="4"} 1
+        grpc_server_requests_per_connection_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_local_addr="1.2.3.4:80",le="16"} 1
+        grpc_server_requests_per_connection_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_local_addr="1.2.3.4:80",le="64"} 1
+        grpc_server_requests_per_connection_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_local_addr="1.2.3.4:80",le="256"} 1
+        grpc_server_requests_per_connection_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_local_addr="1.2.3.4:80",le="1024"} 1
+        grpc_server_requests_per_connection_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_local_addr="1.2.3.4:80",le="4096"} 1
+        grpc_server_requests_per_connection_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_local_addr="1.2.3.4:80",le="16384"} 1
+        grpc_server_requests_per_connection_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_local_addr="1.2.3.4:80",le="65536"} 1
+        grpc_server_requests_per_connection_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_local_addr="1.2.3.4:80",le="262144"} 1
+        grpc_server_requests_per_connection_histogram_bucket{grpc_client_user_agent="fake-user-agent",grpc_local_addr="1.2.3.4:80",le="+Inf"} 1
+        grpc_server_requests_per_connection_histogram_sum{grpc_client_user_agent="fake-user-agent",grpc_local_addr="1.2.3.4:80"} 3
+        grpc_server_requests_per_connection_histogram_count{grpc_client_user_agent="fake-user-agent",grpc_local_addr="1.2.3.4:80"} 1
+	`
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_server_requests_per_connection_histogram"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -26,7 +26,8 @@ type ServerRequestsTotalStatsHandler struct {
 func NewServerRequestsTotalStatsHandler(vec *prometheus.CounterVec, opts ...StatsHandlerOption) *ServerRequestsTotalStatsHandler {
 	h := &ServerRequestsTotalStatsHandler{
 		baseStatsHandler: baseStatsHandler{
-			collector: vec,
+			collector:       vec,
+			supportedLabels: optionalLabelType,
 			options: statsHandlerOptions{
 				handleRPCLabelFn: serverRequestsTotalLabels,
 			},
@@ -43,13 +44,20 @@ func (h *ServerRequestsTotalStatsHandler) HandleRPC(ctx context.Context, stat st
 	if beg, ok := stat.(*stats.Begin); ok {
 		switch {
 		case !beg.IsClient():
-			h.vec.WithLabelValues(h.options.handleRPCLabelFn(ctx, stat)...).Inc()
+			h.inc(ctx, h.vec.WithLabelValues(h.labelValues(ctx, stat)...))
 		}
 	}
 }
 
+// Preinitialize creates series of every method a server exposes, see StatsHandler.Preinitialize.
+func (h *ServerRequestsTotalStatsHandler) Preinitialize(provider ServiceInfoProvider) {
+	for _, lvs := range h.preinitializedLabels(provider, &stats.Begin{}) {
+		h.vec.WithLabelValues(lvs...)
+	}
+}
+
 func serverRequestsTotalLabels(ctx context.Context, _ stats.RPCStats) []string {
-	tag := ctx.Value(tagRPCKey).(rpcTagLabels)
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
 	return []string{
 		tag.method,
 		tag.service,
@@ -9,7 +9,9 @@ import (
 	"google.golang.org/grpc/metadata"
 
 	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/piotrkowalczuk/promgrpc/v4/pb/private/test"
 	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/stats"
 )
 
@@ -41,3 +43,30 @@ func TestNewServerRequestsTotalStatsHandler(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestServerRequestsTotalStatsHandler_Preinitialize(t *testing.T) {
+	h := promgrpc.ServerStatsHandler(
+		promgrpc.StatsHandlerWithMethodFilter(promgrpc.MethodDenylist("*/Bidirectional")),
+	)
+	srv := grpc.NewServer(grpc.StatsHandler(h))
+	test.RegisterTestServiceServer(srv, newDemoServer())
+	h.Preinitialize(srv)
+
+	const metadata = `
+		# HELP grpc_server_requests_received_total TODO
+		# TYPE grpc_server_requests_received_total counter
+	`
+	expected := `
+		grpc_server_requests_received_total{grpc_method="ClientSide",grpc_service="piotrkowalczuk.promgrpc.v4.test.TestService"} 0
+		grpc_server_requests_received_total{grpc_method="ServerSide",grpc_service="piotrkowalczuk.promgrpc.v4.test.TestService"} 0
+		grpc_server_requests_received_total{grpc_method="Unary",grpc_service="piotrkowalczuk.promgrpc.v4.test.TestService"} 0
+	`
+
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_server_requests_received_total"); err != nil {
+		t.Fatal(err)
+	}
+	// Labels of responses depend on a user agent, they are not known up front.
+	if n := testutil.CollectAndCount(h, "grpc_server_responses_sent_total"); n != 0 {
+		t.Fatalf("unexpected number of series, expected 0 but got %d", n)
+	}
+}
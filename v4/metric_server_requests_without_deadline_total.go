@@ -0,0 +1,59 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewServerRequestsWithoutDeadlineTotalCounterVec allocates a new Prometheus CounterVec for the server and given set of options.
+func NewServerRequestsWithoutDeadlineTotalCounterVec(opts ...CollectorOption) *prometheus.CounterVec {
+	labels := []string{
+		labelClientUserAgent,
+		labelMethod,
+		labelService,
+	}
+	return newRequestsWithoutDeadlineTotalCounterVec("server", "requests_received_without_deadline_total", "Number of RPCs received without a deadline.", labels, opts...)
+}
+
+// ServerRequestsWithoutDeadlineTotalStatsHandler is responsible for counting incoming RPCs that have no deadline set.
+// Such RPCs can run for as long as the server lets them, even if nobody waits for the response anymore.
+type ServerRequestsWithoutDeadlineTotalStatsHandler struct {
+	baseStatsHandler
+	vec *prometheus.CounterVec
+}
+
+// NewServerRequestsWithoutDeadlineTotalStatsHandler ...
+func NewServerRequestsWithoutDeadlineTotalStatsHandler(vec *prometheus.CounterVec, opts ...StatsHandlerOption) *ServerRequestsWithoutDeadlineTotalStatsHandler {
+	h := &ServerRequestsWithoutDeadlineTotalStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+			options: statsHandlerOptions{
+				handleRPCLabelFn: serverRequestsWithoutDeadlineTotalLabels,
+			},
+		},
+		vec: vec,
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ServerRequestsWithoutDeadlineTotalStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if _, ok := stat.(*stats.Begin); ok && !stat.IsClient() {
+		if _, ok := ctx.Deadline(); !ok {
+			h.inc(ctx, h.vec.WithLabelValues(h.labelValues(ctx, stat)...))
+		}
+	}
+}
+
+func serverRequestsWithoutDeadlineTotalLabels(ctx context.Context, _ stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	return []string{
+		tag.clientUserAgent,
+		tag.method,
+		tag.service,
+	}
+}
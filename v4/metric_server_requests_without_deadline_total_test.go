@@ -0,0 +1,43 @@
+package promgrpc_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+)
+
+func TestNewServerRequestsWithoutDeadlineTotalStatsHandler(t *testing.T) {
+	h := promgrpc.NewStatsHandler(promgrpc.NewServerRequestsWithoutDeadlineTotalStatsHandler(promgrpc.NewServerRequestsWithoutDeadlineTotalCounterVec()))
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.MD{"user-agent": []string{"fake-user-agent"}})
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+		FullMethodName: "/service/Method",
+		FailFast:       true,
+	})
+	h.HandleRPC(ctx, &stats.Begin{})
+	h.HandleRPC(ctx, &stats.Begin{
+		Client: true,
+	})
+
+	ctxWithDeadline, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	h.HandleRPC(ctxWithDeadline, &stats.Begin{})
+
+	const metadata = `
+		# HELP grpc_server_requests_received_without_deadline_total Number of RPCs received without a deadline.
+        # TYPE grpc_server_requests_received_without_deadline_total counter
+	`
+	expected := `
+		grpc_server_requests_received_without_deadline_total{grpc_client_user_agent="fake-user-agent",grpc_method="Method",grpc_service="service"} 1
+	`
+
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_server_requests_received_without_deadline_total"); err != nil {
+		t.Fatal(err)
+	}
+}
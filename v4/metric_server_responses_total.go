@@ -31,7 +31,8 @@ type ServerResponsesTotalStatsHandler struct {
 func NewServerResponsesTotalStatsHandler(vec *prometheus.CounterVec, opts ...StatsHandlerOption) *ServerResponsesTotalStatsHandler {
 	h := &ServerResponsesTotalStatsHandler{
 		baseStatsHandler: baseStatsHandler{
-			collector: vec,
+			collector:       vec,
+			supportedLabels: optionalLabelType,
 			options: statsHandlerOptions{
 				handleRPCLabelFn: serverResponsesTotalLabels,
 			},
@@ -48,13 +49,13 @@ func (h *ServerResponsesTotalStatsHandler) HandleRPC(ctx context.Context, stat s
 	if _, ok := stat.(*stats.End); ok {
 		switch {
 		case !stat.IsClient():
-			h.vec.WithLabelValues(h.options.handleRPCLabelFn(ctx, stat)...).Inc()
+			h.inc(ctx, h.vec.WithLabelValues(h.labelValues(ctx, stat)...))
 		}
 	}
 }
 
 func serverResponsesTotalLabels(ctx context.Context, stat stats.RPCStats) []string {
-	tag := ctx.Value(tagRPCKey).(rpcTagLabels)
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
 	return []string{
 		tag.clientUserAgent,
 		status.Code(stat.(*stats.End).Error).String(),
@@ -45,3 +45,36 @@ func TestNewServerResponsesTotalStatsHandler(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestNewServerResponsesTotalStatsHandler_typeLabel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.ServerStatsHandler(
+		promgrpc.WithTypeLabel(),
+	)
+	ctx = metadata.NewIncomingContext(ctx, metadata.MD{"user-agent": []string{"fake-user-agent"}})
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+		FullMethodName: "/service/Method",
+	})
+	h.HandleRPC(ctx, &stats.InHeader{})
+	h.HandleRPC(ctx, &stats.Begin{
+		IsServerStream: true,
+	})
+	h.HandleRPC(ctx, &stats.End{})
+
+	const metadata = `
+		# HELP grpc_server_requests_received_total TODO
+        # TYPE grpc_server_requests_received_total counter
+		# HELP grpc_server_responses_sent_total TODO
+        # TYPE grpc_server_responses_sent_total counter
+	`
+	expected := `
+		grpc_server_requests_received_total{grpc_method="Method",grpc_service="service",grpc_type="server_stream"} 1
+		grpc_server_responses_sent_total{grpc_client_user_agent="fake-user-agent",grpc_code="OK",grpc_method="Method",grpc_service="service",grpc_type="server_stream"} 1
+	`
+
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_server_requests_received_total", "grpc_server_responses_sent_total"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,66 @@
+package promgrpc
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+// NewServerStartedCounterVec allocates a new Prometheus CounterVec for the server and given set of options.
+// It is compatible with go-grpc-prometheus.
+func NewServerStartedCounterVec(opts ...CollectorOption) *prometheus.CounterVec {
+	labels := []string{
+		// keep alphabetical order
+		labelMethod,
+		labelService,
+		labelType,
+	}
+	return newStartedTotalCounterVec("server", "started_total", "Total number of RPCs started on the server.", labels, opts...)
+}
+
+// ServerStartedStatsHandler is responsible for counting incoming RPCs as they begin, the same way go-grpc-prometheus does (grpc_server_started_total).
+type ServerStartedStatsHandler struct {
+	baseStatsHandler
+	vec *prometheus.CounterVec
+}
+
+// NewServerStartedStatsHandler ...
+func NewServerStartedStatsHandler(vec *prometheus.CounterVec, opts ...StatsHandlerOption) *ServerStartedStatsHandler {
+	h := &ServerStartedStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+			options: statsHandlerOptions{
+				handleRPCLabelFn: serverStartedLabels,
+			},
+		},
+		vec: vec,
+	}
+	h.applyOpts(opts...)
+
+	return h
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *ServerStartedStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if _, ok := stat.(*stats.Begin); ok && !stat.IsClient() {
+		h.inc(ctx, h.vec.WithLabelValues(h.labelValues(ctx, stat)...))
+	}
+}
+
+// Preinitialize creates series of every method a server exposes, see StatsHandler.Preinitialize.
+func (h *ServerStartedStatsHandler) Preinitialize(provider ServiceInfoProvider) {
+	for _, lvs := range h.preinitializedLabels(provider, &stats.Begin{}) {
+		h.vec.WithLabelValues(lvs...)
+	}
+}
+
+func serverStartedLabels(ctx context.Context, _ stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	// keep alphabetical order
+	return []string{
+		tag.method,
+		tag.service,
+		tag.rpcType,
+	}
+}
@@ -2,6 +2,8 @@ package promgrpc
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"google.golang.org/grpc"
@@ -18,19 +20,31 @@ type statsHandlerOptions struct {
 	// Use IsClient method if available.
 	client           bool
 	handleRPCLabelFn HandleRPCLabelFunc
-	tagRPCLabelFn    TagRPCLabelFunc
+	// customHandleRPCLabelFn is true if handleRPCLabelFn is not the default one, which means labels are not known up front.
+	customHandleRPCLabelFn bool
+	tagRPCLabelFn          TagRPCLabelFunc
+	optionalLabels         optionalLabels
+	exemplarFn             ExemplarFunc
+	customLabelsFn         CustomLabelsFunc
+	customLabels           []string
+	metadataLabels         []metadataLabel
+	methodFilter           MethodFilterFunc
+	registeredMethods      registeredMethods
+	// methodVecs are keyed by a method name without the leading slash, the same way rpcTagLabels.fullMethod is.
+	methodVecs       map[string]prometheus.ObserverVec
+	methodCollectors []prometheus.Collector
 }
 
 // StatsHandlerOption configures a stats handler behaviour.
 type StatsHandlerOption interface {
-	apply(*statsHandlerOptions)
+	applyStatsHandler(*statsHandlerOptions)
 }
 
 type funcStatsHandlerOption struct {
 	f func(*statsHandlerOptions)
 }
 
-func (o *funcStatsHandlerOption) apply(in *statsHandlerOptions) {
+func (o *funcStatsHandlerOption) applyStatsHandler(in *statsHandlerOptions) {
 	o.f(in)
 }
 
@@ -46,41 +60,122 @@ type ShareableStatsHandlerOption interface {
 	StatsHandlerOption
 }
 
+type funcShareableStatsHandlerOption struct {
+	funcStatsHandlerOption
+}
+
+func (o *funcShareableStatsHandlerOption) shareable() {}
+
+func newFuncShareableStatsHandlerOption(f func(*statsHandlerOptions)) *funcShareableStatsHandlerOption {
+	return &funcShareableStatsHandlerOption{
+		funcStatsHandlerOption: funcStatsHandlerOption{f: f},
+	}
+}
+
 // StatsHandlerWithHandleRPCLabelsFunc allows to inject custom HandleRPCLabelFunc to a stats handler.
 // It is not shareable because there little to no chance that all stats handlers need the same set of labels.
 func StatsHandlerWithHandleRPCLabelsFunc(fn HandleRPCLabelFunc) StatsHandlerOption {
 	return newFuncStatsHandlerOption(func(o *statsHandlerOptions) {
 		o.handleRPCLabelFn = fn
+		o.customHandleRPCLabelFn = true
 	})
 }
 
 // StatsHandlerWithTagRPCLabelsFunc allows to inject custom TagRPCLabelFunc to a stats handler.
 // It is not shareable because of performance reasons.
-// If all stats handlers require the same set of additional labels, it is better to implement a custom coordinator
-// (e.g. by embedding StatsHandler) with self-defined TagRPC method.
-// That way, it is guaranteed that new tagging execute only once and default implementation be overridden.
+// If all stats handlers require the same set of additional labels, use WithCustomLabels instead.
+// That way, it is guaranteed that new tagging execute only once.
 func StatsHandlerWithTagRPCLabelsFunc(fn TagRPCLabelFunc) StatsHandlerOption {
 	return newFuncStatsHandlerOption(func(o *statsHandlerOptions) {
 		o.tagRPCLabelFn = fn
 	})
 }
 
+// StatsHandlerWithExemplars returns a ShareableStatsHandlerOption which makes histograms and counters record an exemplar
+// with labels returned by a given function, e.g. a trace ID.
+// Exemplars that exceed prometheus.ExemplarMaxRunes or have invalid label names are dropped, the observation itself is not.
+// Exemplars are exposed only if a registry is served using the OpenMetrics format.
+func StatsHandlerWithExemplars(fn ExemplarFunc) ShareableStatsHandlerOption {
+	return newFuncShareableStatsHandlerOption(func(o *statsHandlerOptions) {
+		o.exemplarFn = fn
+	})
+}
+
+// StatsHandlerWithMethodFilter returns a ShareableStatsHandlerOption which makes a coordinator skip RPCs rejected by a given filter,
+// e.g. health checks. The decision is made once, in TagRPC, and skipped RPCs are not passed to any stats handler,
+// except for the beginning and the end of them, that stats handlers keeping track of connections (e.g. ServerIdleConnectionsStatsHandler) still see.
+// That way, a connection that carries only skipped RPCs is not reported as idle.
+// It takes effect at the coordinator level, see NewStatsHandlerWithOptions.
+func StatsHandlerWithMethodFilter(fn MethodFilterFunc) ShareableStatsHandlerOption {
+	return newFuncShareableStatsHandlerOption(func(o *statsHandlerOptions) {
+		o.methodFilter = fn
+	})
+}
+
+// StatsHandlerWithRegisteredMethods returns a ShareableStatsHandlerOption which makes a coordinator report methods
+// other than given ones (e.g. /package.Service/Method) with grpc_service and grpc_method labels set to unknown.
+// It protects a server from an unbounded number of series, created by clients that call random methods.
+// It takes effect at the coordinator level, see NewStatsHandlerWithOptions and StatsHandler.RegisterServiceInfo.
+func StatsHandlerWithRegisteredMethods(methods ...string) ShareableStatsHandlerOption {
+	rm := newRegisteredMethods(methods)
+	return newFuncShareableStatsHandlerOption(func(o *statsHandlerOptions) {
+		o.registeredMethods = rm
+	})
+}
+
+// StatsHandlerWithMethodVecs returns a StatsHandlerOption which makes a histogram stats handler record observations
+// of given methods (e.g. /package.Service/Method) in dedicated vectors, instead of the one passed to its constructor.
+// That way, methods of very different characteristics (e.g. batch jobs and cache lookups) can have different buckets.
+// Vectors are collected by the stats handler, the same way the default one is.
+// See NewMethodHistogramVecs.
+func StatsHandlerWithMethodVecs(vecs map[string]prometheus.ObserverVec) StatsHandlerOption {
+	return newFuncStatsHandlerOption(func(o *statsHandlerOptions) {
+		o.methodVecs = make(map[string]prometheus.ObserverVec, len(vecs))
+		o.methodCollectors = nil
+
+	Vecs:
+		for method, vec := range vecs {
+			o.methodVecs[strings.TrimPrefix(method, "/")] = vec
+			for _, c := range o.methodCollectors {
+				if c == vec {
+					continue Vecs
+				}
+			}
+			o.methodCollectors = append(o.methodCollectors, vec)
+		}
+	})
+}
+
 type collectorOptions struct {
-	namespace   string
-	userAgent   string
-	constLabels prometheus.Labels
+	namespace      string
+	userAgent      string
+	constLabels    prometheus.Labels
+	optionalLabels optionalLabels
+	// Native histograms settings, see prometheus.HistogramOpts for details.
+	nativeHistogramBucketFactor    float64
+	nativeHistogramMaxBucketNumber uint32
+	nativeHistogramZeroThreshold   float64
+	withoutClassicBuckets          bool
+	buckets                        []float64
+	customLabels                   []string
+	metadataLabels                 []string
+	// Sketch summaries settings, see SketchSummaryOpts for details.
+	summaryQuantiles       []float64
+	summaryMaxAge          time.Duration
+	summaryAgeBuckets      uint32
+	sketchRelativeAccuracy float64
 }
 
 // CollectorOption configures a collector.
 type CollectorOption interface {
-	apply(*collectorOptions)
+	applyCollector(*collectorOptions)
 }
 
 type funcCollectorOption struct {
 	f func(*collectorOptions)
 }
 
-func (o *funcCollectorOption) apply(in *collectorOptions) {
+func (o *funcCollectorOption) applyCollector(in *collectorOptions) {
 	o.f(in)
 }
 
@@ -108,6 +203,102 @@ func newFuncShareableCollectorOption(f func(*collectorOptions)) *funcShareableCo
 	}
 }
 
+// ShareableLabelOption is an option that is both ShareableCollectorOption and ShareableStatsHandlerOption.
+// It adds a label to collectors and makes stats handlers report its value, so that both always agree on the set of labels.
+// Coordinator constructors (e.g. ServerStatsHandler) pass it to both on their own.
+// If a collector and a stats handler are allocated directly, it has to be passed to each of them.
+type ShareableLabelOption interface {
+	ShareableOption
+	CollectorOption
+	StatsHandlerOption
+}
+
+type funcShareableLabelOption struct {
+	collector    func(*collectorOptions)
+	statsHandler func(*statsHandlerOptions)
+}
+
+func (o *funcShareableLabelOption) shareable() {}
+
+func (o *funcShareableLabelOption) applyCollector(in *collectorOptions) {
+	o.collector(in)
+}
+
+func (o *funcShareableLabelOption) applyStatsHandler(in *statsHandlerOptions) {
+	o.statsHandler(in)
+}
+
+func newFuncShareableLabelOption(collector func(*collectorOptions), statsHandler func(*statsHandlerOptions)) *funcShareableLabelOption {
+	return &funcShareableLabelOption{
+		collector:    collector,
+		statsHandler: statsHandler,
+	}
+}
+
+// WithCompressionLabel returns a ShareableLabelOption which adds grpc_compression label to message size related collectors,
+// and makes their stats handlers report the name of a compressor (e.g. gzip or identity) used to encode a message.
+func WithCompressionLabel() ShareableLabelOption {
+	return newFuncShareableLabelOption(func(o *collectorOptions) {
+		o.optionalLabels |= optionalLabelCompression
+	}, func(o *statsHandlerOptions) {
+		o.optionalLabels |= optionalLabelCompression
+	})
+}
+
+// WithTypeLabel returns a ShareableLabelOption which adds grpc_type label to requests, responses and request duration collectors,
+// and makes their stats handlers report the type of an RPC (unary, client_stream, server_stream or bidi_stream).
+func WithTypeLabel() ShareableLabelOption {
+	return newFuncShareableLabelOption(func(o *collectorOptions) {
+		o.optionalLabels |= optionalLabelType
+	}, func(o *statsHandlerOptions) {
+		o.optionalLabels |= optionalLabelType
+	})
+}
+
+// WithMessageTypeLabel returns a ShareableLabelOption which adds grpc_message_type label to message size and message count collectors,
+// and makes their stats handlers report the full protobuf name of a message (e.g. google.protobuf.Empty).
+// google.protobuf.Any and messages made of a single oneof are unpacked to the concrete type.
+// Since the concrete type is chosen by the peer, it has to be used with caution.
+func WithMessageTypeLabel() ShareableLabelOption {
+	return newFuncShareableLabelOption(func(o *collectorOptions) {
+		o.optionalLabels |= optionalLabelMessageType
+	}, func(o *statsHandlerOptions) {
+		o.optionalLabels |= optionalLabelMessageType
+	})
+}
+
+// WithCustomLabels returns a ShareableLabelOption which adds labels of given names (e.g. a tenant) to RPC related collectors,
+// and makes their stats handlers report values returned by a given function.
+// The function is called once per RPC by a coordinator (see NewStatsHandlerWithOptions), and its result is shared by all stats handlers.
+// Values have to be returned in the same order as names are given, missing ones are reported as n/a and excess ones are dropped.
+// Connection related stats handlers are not affected, neither are the stats handlers of V3ClientStatsHandler and V3ServerStatsHandler.
+func WithCustomLabels(fn CustomLabelsFunc, names ...string) ShareableLabelOption {
+	return newFuncShareableLabelOption(func(o *collectorOptions) {
+		o.customLabels = names
+	}, func(o *statsHandlerOptions) {
+		o.customLabelsFn = fn
+		o.customLabels = names
+	})
+}
+
+// WithMetadataLabels returns a ShareableLabelOption which adds labels mapped from incoming metadata (e.g. x-tenant-tier)
+// to RPC related collectors, and makes their stats handlers report them.
+// Values that are not allowed are reported as other, missing keys as n/a.
+// Metadata is read once per RPC by a coordinator (see NewStatsHandlerWithOptions), hence it is meant for server-side coordinators.
+// Connection related stats handlers are not affected, neither are the stats handlers of V3ClientStatsHandler and V3ServerStatsHandler.
+func WithMetadataLabels(labels ...MetadataLabel) ShareableLabelOption {
+	mls := newMetadataLabels(labels)
+	names := make([]string, 0, len(labels))
+	for _, l := range labels {
+		names = append(names, l.Name)
+	}
+	return newFuncShareableLabelOption(func(o *collectorOptions) {
+		o.metadataLabels = names
+	}, func(o *statsHandlerOptions) {
+		o.metadataLabels = mls
+	})
+}
+
 // CollectorWithNamespace returns a ShareableCollectorOption which sets namespace of a collector.
 func CollectorWithNamespace(namespace string) ShareableCollectorOption {
 	return newFuncShareableCollectorOption(func(o *collectorOptions) {
@@ -129,11 +320,120 @@ func CollectorWithConstLabels(constLabels prometheus.Labels) ShareableCollectorO
 	})
 }
 
-func applyCollectorOptions(prototype prometheus.Opts, opts ...CollectorOption) prometheus.Opts {
+// CollectorWithNativeHistogramBucketFactor returns a ShareableCollectorOption which makes histogram collectors emit native histograms.
+// The factor has to be greater than one and defines the maximum growth of a bucket width, e.g. 1.1 means 10%.
+// By default, native histograms are emitted alongside classic buckets, use CollectorWithoutClassicBuckets to emit them alone.
+func CollectorWithNativeHistogramBucketFactor(factor float64) ShareableCollectorOption {
+	return newFuncShareableCollectorOption(func(o *collectorOptions) {
+		o.nativeHistogramBucketFactor = factor
+	})
+}
+
+// CollectorWithNativeHistogramMaxBucketNumber returns a ShareableCollectorOption which limits the number of buckets of a native histogram.
+// Once exceeded, the resolution is reduced.
+func CollectorWithNativeHistogramMaxBucketNumber(n uint32) ShareableCollectorOption {
+	return newFuncShareableCollectorOption(func(o *collectorOptions) {
+		o.nativeHistogramMaxBucketNumber = n
+	})
+}
+
+// CollectorWithNativeHistogramZeroThreshold returns a ShareableCollectorOption which sets the width of the zero bucket of a native histogram.
+// Observations whose absolute value is lower or equal to the threshold are counted in the zero bucket.
+// Use prometheus.NativeHistogramZeroThresholdZero to count only exact zeros.
+func CollectorWithNativeHistogramZeroThreshold(threshold float64) ShareableCollectorOption {
+	return newFuncShareableCollectorOption(func(o *collectorOptions) {
+		o.nativeHistogramZeroThreshold = threshold
+	})
+}
+
+// CollectorWithoutClassicBuckets returns a ShareableCollectorOption which makes histogram collectors emit native histograms only.
+// It has an effect only together with CollectorWithNativeHistogramBucketFactor, otherwise classic buckets are kept.
+func CollectorWithoutClassicBuckets() ShareableCollectorOption {
+	return newFuncShareableCollectorOption(func(o *collectorOptions) {
+		o.withoutClassicBuckets = true
+	})
+}
+
+// CollectorWithBuckets returns a CollectorOption which overrides default buckets of a histogram collector.
+// It is not shareable because different metrics (e.g. durations and sizes) hardly ever share a bucket layout.
+func CollectorWithBuckets(buckets ...float64) CollectorOption {
+	return newFuncCollectorOption(func(o *collectorOptions) {
+		o.buckets = buckets
+	})
+}
+
+// CollectorWithSummaryQuantiles returns a ShareableCollectorOption which overrides quantiles reported by sketch summary collectors.
+// See SketchSummaryOpts.
+func CollectorWithSummaryQuantiles(quantiles ...float64) ShareableCollectorOption {
+	return newFuncShareableCollectorOption(func(o *collectorOptions) {
+		o.summaryQuantiles = quantiles
+	})
+}
+
+// CollectorWithSummaryMaxAge returns a ShareableCollectorOption which sets the length of a sliding time window
+// quantiles of sketch summary collectors are computed over.
+func CollectorWithSummaryMaxAge(maxAge time.Duration) ShareableCollectorOption {
+	return newFuncShareableCollectorOption(func(o *collectorOptions) {
+		o.summaryMaxAge = maxAge
+	})
+}
+
+// CollectorWithSummaryAgeBuckets returns a ShareableCollectorOption which sets the number of sketches
+// a sliding time window of sketch summary collectors is divided into.
+// The more buckets, the smoother the window slides, at the cost of memory.
+func CollectorWithSummaryAgeBuckets(n uint32) ShareableCollectorOption {
+	return newFuncShareableCollectorOption(func(o *collectorOptions) {
+		o.summaryAgeBuckets = n
+	})
+}
+
+// CollectorWithSketchRelativeAccuracy returns a ShareableCollectorOption which sets the relative accuracy
+// of quantiles reported by sketch summary collectors, e.g. 0.01 means 1%.
+func CollectorWithSketchRelativeAccuracy(accuracy float64) ShareableCollectorOption {
+	return newFuncShareableCollectorOption(func(o *collectorOptions) {
+		o.sketchRelativeAccuracy = accuracy
+	})
+}
+
+// NewMethodHistogramVecs allocates a histogram vector for each distinct bucket layout of given methods (e.g. /package.Service/Method).
+// Collectors are allocated by a given constructor, e.g. NewServerRequestDurationHistogramVec, with CollectorWithBuckets appended to the options.
+// The result is meant to be passed to StatsHandlerWithMethodVecs.
+func NewMethodHistogramVecs(newVec func(...CollectorOption) *prometheus.HistogramVec, buckets map[string][]float64, opts ...CollectorOption) map[string]prometheus.ObserverVec {
+	var (
+		vecs    = make(map[string]prometheus.ObserverVec, len(buckets))
+		layouts = make(map[string]prometheus.ObserverVec)
+	)
+	for method, layout := range buckets {
+		key := fmt.Sprint(layout)
+		if _, ok := layouts[key]; !ok {
+			layouts[key] = newVec(append(opts[:len(opts):len(opts)], CollectorWithBuckets(layout...))...)
+		}
+		vecs[method] = layouts[key]
+	}
+	return vecs
+}
+
+func newCollectorOptions(opts ...CollectorOption) collectorOptions {
 	var options collectorOptions
 	for _, opt := range opts {
-		opt.apply(&options)
+		opt.applyCollector(&options)
 	}
+	return options
+}
+
+// applyLabelOptions appends names of optional labels that are both supported by a collector and enabled by the options,
+// followed by names of custom labels and metadata labels.
+func applyLabelOptions(labels []string, supported optionalLabels, opts ...CollectorOption) []string {
+	options := newCollectorOptions(opts...)
+	if enabled := options.optionalLabels & supported; enabled != 0 {
+		labels = append(labels, enabled.names()...)
+	}
+	labels = append(labels, options.customLabels...)
+	return append(labels, options.metadataLabels...)
+}
+
+func applyCollectorOptions(prototype prometheus.Opts, opts ...CollectorOption) prometheus.Opts {
+	options := newCollectorOptions(opts...)
 
 	if options.namespace != "" {
 		prototype.Namespace = options.namespace
@@ -146,10 +446,36 @@ func applyCollectorOptions(prototype prometheus.Opts, opts ...CollectorOption) p
 }
 
 func applyHistogramOptions(prototype prometheus.HistogramOpts, opts ...CollectorOption) prometheus.HistogramOpts {
-	var options collectorOptions
-	for _, opt := range opts {
-		opt.apply(&options)
+	options := newCollectorOptions(opts...)
+
+	if options.namespace != "" {
+		prototype.Namespace = options.namespace
 	}
+	if options.constLabels != nil {
+		prototype.ConstLabels = options.constLabels
+	}
+	if options.buckets != nil {
+		prototype.Buckets = options.buckets
+	}
+	if options.nativeHistogramBucketFactor > 1 {
+		prototype.NativeHistogramBucketFactor = options.nativeHistogramBucketFactor
+		prototype.NativeHistogramMaxBucketNumber = options.nativeHistogramMaxBucketNumber
+		prototype.NativeHistogramZeroThreshold = options.nativeHistogramZeroThreshold
+
+		switch {
+		case options.withoutClassicBuckets:
+			prototype.Buckets = nil
+		case len(prototype.Buckets) == 0:
+			// Otherwise, prometheus would not fall back to the default buckets.
+			prototype.Buckets = prometheus.DefBuckets
+		}
+	}
+
+	return prototype
+}
+
+func applySketchSummaryOptions(prototype SketchSummaryOpts, opts ...CollectorOption) SketchSummaryOpts {
+	options := newCollectorOptions(opts...)
 
 	if options.namespace != "" {
 		prototype.Namespace = options.namespace
@@ -157,6 +483,18 @@ func applyHistogramOptions(prototype prometheus.HistogramOpts, opts ...Collector
 	if options.constLabels != nil {
 		prototype.ConstLabels = options.constLabels
 	}
+	if options.summaryQuantiles != nil {
+		prototype.Quantiles = options.summaryQuantiles
+	}
+	if options.summaryMaxAge > 0 {
+		prototype.MaxAge = options.summaryMaxAge
+	}
+	if options.summaryAgeBuckets > 0 {
+		prototype.AgeBuckets = options.summaryAgeBuckets
+	}
+	if options.sketchRelativeAccuracy > 0 {
+		prototype.RelativeAccuracy = options.sketchRelativeAccuracy
+	}
 
 	return prototype
 }
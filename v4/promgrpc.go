@@ -4,14 +4,23 @@ import (
 	"context"
 	"strings"
 
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
 
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/known/anypb"
 )
 
 const (
-	namespace    = "grpc"
-	notAvailable = "n/a"
+	namespace           = "grpc"
+	notAvailable        = "n/a"
+	unknown             = "unknown"
+	identityCompression = "identity"
 )
 
 type ctxKey int
@@ -21,11 +30,69 @@ const (
 	tagConnKey ctxKey = 3
 )
 
+// split returns a service and a method name of a full method name, e.g. /package.Service/Method.
+// Malformed names are reported as unknown.
 func split(name string) (string, string) {
-	if i := strings.LastIndex(name, "/"); i >= 0 {
-		return name[1:i], name[i+1:]
+	if strings.HasPrefix(name, "/") {
+		if i := strings.LastIndex(name, "/"); i > 1 && i < len(name)-1 {
+			return name[1:i], name[i+1:]
+		}
+	}
+	return unknown, unknown
+}
+
+// ServiceInfoProvider is simple wrapper around GetServiceInfo method.
+// This interface is implemented by grpc Server.
+type ServiceInfoProvider interface {
+	// GetServiceInfo returns a map from service names to ServiceInfo.
+	// Service names include the package names, in the form of <package>.<service>.
+	GetServiceInfo() map[string]grpc.ServiceInfo
+}
+
+// registeredMethods is a set of methods exposed by a server, without the leading slash, e.g. package.Service/Method.
+type registeredMethods map[string]struct{}
+
+func newRegisteredMethods(methods []string) registeredMethods {
+	rm := make(registeredMethods, len(methods))
+	for _, m := range methods {
+		rm[strings.TrimPrefix(m, "/")] = struct{}{}
+	}
+	return rm
+}
+
+// serviceInfoMethods lists methods of every service a provider exposes.
+func serviceInfoMethods(provider ServiceInfoProvider) []string {
+	var methods []string
+	for service, info := range provider.GetServiceInfo() {
+		for _, m := range info.Methods {
+			methods = append(methods, service+"/"+m.Name)
+		}
+	}
+	return methods
+}
+
+// filteredServiceInfo is a ServiceInfoProvider that hides methods rejected by a filter.
+type filteredServiceInfo struct {
+	provider ServiceInfoProvider
+	filter   MethodFilterFunc
+}
+
+// GetServiceInfo implements ServiceInfoProvider interface.
+func (f filteredServiceInfo) GetServiceInfo() map[string]grpc.ServiceInfo {
+	infos := make(map[string]grpc.ServiceInfo)
+	for service, info := range f.provider.GetServiceInfo() {
+		var methods []grpc.MethodInfo
+		for _, m := range info.Methods {
+			if f.filter("/" + service + "/" + m.Name) {
+				methods = append(methods, m)
+			}
+		}
+		if len(methods) > 0 {
+			info.Methods = methods
+			infos[service] = info
+		}
 	}
-	return "unknown", "unknown"
+	return infos
 }
 
 func userAgentOnServerSide(ctx context.Context, _ *stats.RPCTagInfo) string {
@@ -36,3 +103,85 @@ func userAgentOnServerSide(ctx context.Context, _ *stats.RPCTagInfo) string {
 	}
 	return notAvailable
 }
+
+// compressor returns a name of a compressor, where no compression is reported as identity.
+func compressor(name string) string {
+	if name == "" {
+		return identityCompression
+	}
+	return name
+}
+
+// statusCodeNames are names of status codes as defined by the gRPC specification, e.g. DEADLINE_EXCEEDED.
+var statusCodeNames = [...]string{
+	codes.OK:                 "OK",
+	codes.Canceled:           "CANCELLED",
+	codes.Unknown:            "UNKNOWN",
+	codes.InvalidArgument:    "INVALID_ARGUMENT",
+	codes.DeadlineExceeded:   "DEADLINE_EXCEEDED",
+	codes.NotFound:           "NOT_FOUND",
+	codes.AlreadyExists:      "ALREADY_EXISTS",
+	codes.PermissionDenied:   "PERMISSION_DENIED",
+	codes.ResourceExhausted:  "RESOURCE_EXHAUSTED",
+	codes.FailedPrecondition: "FAILED_PRECONDITION",
+	codes.Aborted:            "ABORTED",
+	codes.OutOfRange:         "OUT_OF_RANGE",
+	codes.Unimplemented:      "UNIMPLEMENTED",
+	codes.Internal:           "INTERNAL",
+	codes.Unavailable:        "UNAVAILABLE",
+	codes.DataLoss:           "DATA_LOSS",
+	codes.Unauthenticated:    "UNAUTHENTICATED",
+}
+
+// statusCodeName returns a name of a status code an error carries, as defined by the gRPC specification.
+func statusCodeName(err error) string {
+	if code := status.Code(err); int(code) < len(statusCodeNames) {
+		return statusCodeNames[code]
+	}
+	return statusCodeNames[codes.Unknown]
+}
+
+// messageType returns the full protobuf name of a message.
+// google.protobuf.Any and messages made of a single oneof are unpacked, so the name of the concrete type is reported instead.
+// google.protobuf.Any of a type that is not registered in protoregistry.GlobalTypes is reported as it is.
+func messageType(msg any) string {
+	m, ok := msg.(proto.Message)
+	if !ok || m == nil {
+		return notAvailable
+	}
+	return string(messageFullName(m.ProtoReflect()))
+}
+
+func messageFullName(m protoreflect.Message) protoreflect.FullName {
+	for {
+		if a, ok := m.Interface().(*anypb.Any); ok {
+			// The type URL is chosen by the peer, only types linked into the binary are reported.
+			if mt, err := protoregistry.GlobalTypes.FindMessageByName(a.MessageName()); err == nil {
+				return mt.Descriptor().FullName()
+			}
+			return m.Descriptor().FullName()
+		}
+		fd := unionField(m)
+		if fd == nil {
+			return m.Descriptor().FullName()
+		}
+		m = m.Get(fd).Message()
+	}
+}
+
+// unionField returns a populated field of a message that consists of nothing but a single oneof, as long as the field holds a message.
+func unionField(m protoreflect.Message) protoreflect.FieldDescriptor {
+	desc := m.Descriptor()
+	if desc.Oneofs().Len() != 1 {
+		return nil
+	}
+	oneof := desc.Oneofs().Get(0)
+	if oneof.IsSynthetic() || oneof.Fields().Len() != desc.Fields().Len() {
+		return nil
+	}
+	fd := m.WhichOneof(oneof)
+	if fd == nil || fd.Message() == nil {
+		return nil
+	}
+	return fd
+}
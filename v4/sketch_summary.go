@@ -0,0 +1,202 @@
+package promgrpc
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4/internal/sketch"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// DefSketchRelativeAccuracy is the default relative accuracy of quantiles reported by SketchSummaryVec.
+	DefSketchRelativeAccuracy = 0.01
+	// DefSketchMaxBins is the default maximum number of bins of a single sketch.
+	// Together with the default relative accuracy, it covers values from microseconds up to days without losing accuracy.
+	DefSketchMaxBins = 2048
+)
+
+// DefSketchQuantiles are the default quantiles reported by SketchSummaryVec.
+var DefSketchQuantiles = []float64{0.5, 0.9, 0.99, 0.999}
+
+// SketchSummaryOpts bundles the options for creating a SketchSummaryVec.
+// Name is mandatory, zero values of the remaining fields fall back to defaults.
+type SketchSummaryOpts struct {
+	Namespace   string
+	Subsystem   string
+	Name        string
+	Help        string
+	ConstLabels prometheus.Labels
+	// Quantiles to report, each within the range of [0, 1]. Defaults to DefSketchQuantiles.
+	Quantiles []float64
+	// MaxAge defines the duration for which an observation stays relevant for quantiles. Defaults to prometheus.DefMaxAge.
+	MaxAge time.Duration
+	// AgeBuckets is the number of sketches the MaxAge is divided into. Defaults to prometheus.DefAgeBuckets.
+	AgeBuckets uint32
+	// RelativeAccuracy of reported quantiles, e.g. 0.01 means that p99 is within 1% of its exact value.
+	// It has to be within the range of (0, 1). Defaults to DefSketchRelativeAccuracy.
+	RelativeAccuracy float64
+	// MaxBins bounds the memory of a single sketch. Defaults to DefSketchMaxBins.
+	MaxBins int
+}
+
+// SketchSummaryVec is a prometheus.ObserverVec that, unlike prometheus.SummaryVec, keeps a mergeable DDSketch per series.
+// It is exposed as a regular Prometheus summary, but quantiles, even the highest ones, have a bounded relative error
+// and memory usage does not depend on the number of observations.
+// Like in a Prometheus summary, quantiles are computed over a sliding time window, while count and sum are cumulative.
+// It is an alternative to histograms for RPC duration stats handlers, e.g. NewServerRequestDurationStatsHandler.
+type SketchSummaryVec struct {
+	*prometheus.MetricVec
+}
+
+// NewSketchSummaryVec creates a new SketchSummaryVec based on the provided SketchSummaryOpts and partitioned by the given label names.
+func NewSketchSummaryVec(opts SketchSummaryOpts, labelNames []string) *SketchSummaryVec {
+	if len(opts.Quantiles) == 0 {
+		opts.Quantiles = DefSketchQuantiles
+	}
+	for _, q := range opts.Quantiles {
+		if q < 0 || q > 1 || math.IsNaN(q) {
+			panic(fmt.Errorf("illegal quantile %g, has to be within the range of [0, 1]", q))
+		}
+	}
+	if opts.MaxAge <= 0 {
+		opts.MaxAge = prometheus.DefMaxAge
+	}
+	if opts.AgeBuckets == 0 {
+		opts.AgeBuckets = prometheus.DefAgeBuckets
+	}
+	if opts.RelativeAccuracy <= 0 || opts.RelativeAccuracy >= 1 {
+		opts.RelativeAccuracy = DefSketchRelativeAccuracy
+	}
+	if opts.MaxBins <= 0 {
+		opts.MaxBins = DefSketchMaxBins
+	}
+
+	desc := prometheus.NewDesc(
+		prometheus.BuildFQName(opts.Namespace, opts.Subsystem, opts.Name),
+		opts.Help,
+		labelNames,
+		opts.ConstLabels,
+	)
+	return &SketchSummaryVec{
+		MetricVec: prometheus.NewMetricVec(desc, func(lvs ...string) prometheus.Metric {
+			return &sketchSummary{
+				desc:       desc,
+				labelPairs: prometheus.MakeLabelPairs(desc, lvs),
+				quantiles:  opts.Quantiles,
+				window:     sketch.NewWindow(opts.MaxAge, int(opts.AgeBuckets), opts.RelativeAccuracy, opts.MaxBins),
+			}
+		}),
+	}
+}
+
+// GetMetricWithLabelValues returns the summary for the given slice of label values.
+// See prometheus.SummaryVec for details.
+func (v *SketchSummaryVec) GetMetricWithLabelValues(lvs ...string) (prometheus.Observer, error) {
+	metric, err := v.MetricVec.GetMetricWithLabelValues(lvs...)
+	if metric != nil {
+		return metric.(prometheus.Observer), err
+	}
+	return nil, err
+}
+
+// GetMetricWith returns the summary for the given labels.
+// See prometheus.SummaryVec for details.
+func (v *SketchSummaryVec) GetMetricWith(labels prometheus.Labels) (prometheus.Observer, error) {
+	metric, err := v.MetricVec.GetMetricWith(labels)
+	if metric != nil {
+		return metric.(prometheus.Observer), err
+	}
+	return nil, err
+}
+
+// WithLabelValues works as GetMetricWithLabelValues, but panics where GetMetricWithLabelValues would have returned an error.
+func (v *SketchSummaryVec) WithLabelValues(lvs ...string) prometheus.Observer {
+	s, err := v.GetMetricWithLabelValues(lvs...)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// With works as GetMetricWith, but panics where GetMetricWith would have returned an error.
+func (v *SketchSummaryVec) With(labels prometheus.Labels) prometheus.Observer {
+	s, err := v.GetMetricWith(labels)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// CurryWith returns a vector curried with the provided labels.
+// See prometheus.SummaryVec for details.
+func (v *SketchSummaryVec) CurryWith(labels prometheus.Labels) (prometheus.ObserverVec, error) {
+	vec, err := v.MetricVec.CurryWith(labels)
+	if vec != nil {
+		return &SketchSummaryVec{vec}, err
+	}
+	return nil, err
+}
+
+// MustCurryWith works as CurryWith, but panics where CurryWith would have returned an error.
+func (v *SketchSummaryVec) MustCurryWith(labels prometheus.Labels) prometheus.ObserverVec {
+	vec, err := v.CurryWith(labels)
+	if err != nil {
+		panic(err)
+	}
+	return vec
+}
+
+type sketchSummary struct {
+	desc       *prometheus.Desc
+	labelPairs []*dto.LabelPair
+	quantiles  []float64
+
+	lock   sync.Mutex
+	window *sketch.Window
+	count  uint64
+	sum    float64
+}
+
+// Desc implements prometheus.Metric interface.
+func (s *sketchSummary) Desc() *prometheus.Desc {
+	return s.desc
+}
+
+// Observe implements prometheus.Observer interface.
+func (s *sketchSummary) Observe(v float64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.window.Add(time.Now(), v)
+	s.count++
+	s.sum += v
+}
+
+// Write implements prometheus.Metric interface.
+func (s *sketchSummary) Write(out *dto.Metric) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	values := s.window.Quantiles(time.Now(), s.quantiles)
+	quantiles := make([]*dto.Quantile, 0, len(s.quantiles))
+	for i, q := range s.quantiles {
+		quantiles = append(quantiles, &dto.Quantile{
+			Quantile: proto.Float64(q),
+			Value:    proto.Float64(values[i]),
+		})
+	}
+
+	out.Label = s.labelPairs
+	out.Summary = &dto.Summary{
+		SampleCount: proto.Uint64(s.count),
+		SampleSum:   proto.Float64(s.sum),
+		Quantile:    quantiles,
+	}
+	return nil
+}
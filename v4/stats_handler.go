@@ -2,12 +2,22 @@ package promgrpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net"
 	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
 )
 
 // StatsHandlerCollector is a simple wrapper for stats Handler and prometheus Collector interfaces.
@@ -23,6 +33,9 @@ var _ StatsHandlerCollector = &StatsHandler{}
 // That way it reduces context manipulation overhead and improves overall performance.
 type StatsHandler struct {
 	handlers []StatsHandlerCollector
+	options  statsHandlerOptions
+	// registeredMethods can be replaced by RegisterServiceInfo while RPCs are tagged, hence it is kept apart from options.
+	registeredMethods atomic.Pointer[registeredMethods]
 }
 
 // NewStatsHandler allocates a new coordinator.
@@ -33,11 +46,30 @@ func NewStatsHandler(handlers ...StatsHandlerCollector) *StatsHandler {
 	}
 }
 
+// NewStatsHandlerWithOptions allocates a new coordinator, the same way NewStatsHandler does.
+// Additionally, it applies options that take effect at the coordinator level, e.g. StatsHandlerWithMethodFilter.
+// Remaining options are ignored, they have to be passed to stats handlers directly.
+func NewStatsHandlerWithOptions(handlers []StatsHandlerCollector, opts ...StatsHandlerOption) *StatsHandler {
+	h := NewStatsHandler(handlers...)
+	for _, opt := range opts {
+		opt.applyStatsHandler(&h.options)
+	}
+	if h.options.registeredMethods != nil {
+		h.registeredMethods.Store(&h.options.registeredMethods)
+	}
+	return h
+}
+
 // ClientStatsHandler instantiates a default client-side coordinator together with every metric specific stats handler provided by this package.
 func ClientStatsHandler(opts ...ShareableOption) *StatsHandler {
 	collectorOpts, statsHandlerOpts := optionsSplit(opts...)
 
-	return NewStatsHandler(
+	return NewStatsHandlerWithOptions(clientStatsHandlers(collectorOpts, statsHandlerOpts), statsHandlerOpts...)
+}
+
+// clientStatsHandlers returns every metric specific stats handler that ClientStatsHandler is made of.
+func clientStatsHandlers(collectorOpts []CollectorOption, statsHandlerOpts []StatsHandlerOption) []StatsHandlerCollector {
+	return []StatsHandlerCollector{
 		NewClientConnectionsStatsHandler(NewClientConnectionsGaugeVec(collectorOpts...)),
 		NewClientRequestsTotalStatsHandler(NewClientRequestsTotalCounterVec(collectorOpts...), statsHandlerOpts...),
 		NewClientRequestsInFlightStatsHandler(NewClientRequestsInFlightGaugeVec(collectorOpts...), statsHandlerOpts...),
@@ -47,14 +79,19 @@ func ClientStatsHandler(opts ...ShareableOption) *StatsHandler {
 		NewClientMessagesSentTotalStatsHandler(NewClientMessagesSentTotalCounterVec(collectorOpts...), statsHandlerOpts...),
 		NewClientMessageSentSizeStatsHandler(NewClientMessageSentSizeHistogramVec(collectorOpts...), statsHandlerOpts...),
 		NewClientMessageReceivedSizeStatsHandler(NewClientMessageReceivedSizeHistogramVec(collectorOpts...), statsHandlerOpts...),
-	)
+	}
 }
 
 // ServerStatsHandler instantiates a default server-side coordinator together with every metric specific stats handler provided by this package.
 func ServerStatsHandler(opts ...ShareableOption) *StatsHandler {
 	collectorOpts, statsHandlerOpts := optionsSplit(opts...)
 
-	return NewStatsHandler(
+	return NewStatsHandlerWithOptions(serverStatsHandlers(collectorOpts, statsHandlerOpts), statsHandlerOpts...)
+}
+
+// serverStatsHandlers returns every metric specific stats handler that ServerStatsHandler is made of.
+func serverStatsHandlers(collectorOpts []CollectorOption, statsHandlerOpts []StatsHandlerOption) []StatsHandlerCollector {
+	return []StatsHandlerCollector{
 		NewServerConnectionsStatsHandler(NewServerConnectionsGaugeVec(collectorOpts...)),
 		NewServerRequestsTotalStatsHandler(NewServerRequestsTotalCounterVec(collectorOpts...), statsHandlerOpts...),
 		NewServerRequestsInFlightStatsHandler(NewServerRequestsInFlightGaugeVec(collectorOpts...), statsHandlerOpts...),
@@ -64,19 +101,114 @@ func ServerStatsHandler(opts ...ShareableOption) *StatsHandler {
 		NewServerMessagesSentTotalStatsHandler(NewServerMessagesSentTotalCounterVec(collectorOpts...), statsHandlerOpts...),
 		NewServerMessageSentSizeStatsHandler(NewServerMessageSentSizeHistogramVec(collectorOpts...), statsHandlerOpts...),
 		NewServerMessageReceivedSizeStatsHandler(NewServerMessageReceivedSizeHistogramVec(collectorOpts...), statsHandlerOpts...),
-	)
+	}
+}
+
+// A66ClientStatsHandler instantiates a client-side coordinator together with stats handlers that follow metric names, units and labels defined by gRPC proposal A66 (OpenTelemetry Metrics).
+// Names are converted to Prometheus conventions, e.g. grpc.client.attempt.duration becomes grpc_client_attempt_duration_seconds and grpc.method becomes grpc_method label.
+// grpc_client_call_duration_seconds and grpc_target label require UnaryClientInterceptor and StreamClientInterceptor to be installed.
+func A66ClientStatsHandler(opts ...ShareableOption) *StatsHandler {
+	collectorOpts, statsHandlerOpts := optionsSplit(opts...)
+
+	return NewStatsHandlerWithOptions([]StatsHandlerCollector{
+		NewClientAttemptStartedStatsHandler(NewClientAttemptStartedCounterVec(collectorOpts...), statsHandlerOpts...),
+		NewClientAttemptDurationStatsHandler(NewClientAttemptDurationHistogramVec(collectorOpts...), statsHandlerOpts...),
+		NewClientAttemptSentTotalCompressedMessageSizeStatsHandler(NewClientAttemptSentTotalCompressedMessageSizeHistogramVec(collectorOpts...), statsHandlerOpts...),
+		NewClientAttemptRcvdTotalCompressedMessageSizeStatsHandler(NewClientAttemptRcvdTotalCompressedMessageSizeHistogramVec(collectorOpts...), statsHandlerOpts...),
+		NewClientCallDurationStatsHandler(NewClientCallDurationHistogramVec(collectorOpts...), statsHandlerOpts...),
+	}, statsHandlerOpts...)
+}
+
+// A66ServerStatsHandler instantiates a server-side coordinator together with stats handlers that follow metric names, units and labels defined by gRPC proposal A66 (OpenTelemetry Metrics).
+// Names are converted to Prometheus conventions, e.g. grpc.server.call.duration becomes grpc_server_call_duration_seconds and grpc.status becomes grpc_status label.
+// As A66 requires, methods not registered on the server are reported with grpc_method label set to other.
+// Registered methods are given by StatsHandler.RegisterServiceInfo or StatsHandlerWithRegisteredMethods.
+// Until they are, every method is reported as it is, including random ones called by misbehaving clients.
+func A66ServerStatsHandler(opts ...ShareableOption) *StatsHandler {
+	collectorOpts, statsHandlerOpts := optionsSplit(opts...)
+
+	return NewStatsHandlerWithOptions([]StatsHandlerCollector{
+		NewServerCallStartedStatsHandler(NewServerCallStartedCounterVec(collectorOpts...), statsHandlerOpts...),
+		NewServerCallDurationStatsHandler(NewServerCallDurationHistogramVec(collectorOpts...), statsHandlerOpts...),
+		NewServerCallSentTotalCompressedMessageSizeStatsHandler(NewServerCallSentTotalCompressedMessageSizeHistogramVec(collectorOpts...), statsHandlerOpts...),
+		NewServerCallRcvdTotalCompressedMessageSizeStatsHandler(NewServerCallRcvdTotalCompressedMessageSizeHistogramVec(collectorOpts...), statsHandlerOpts...),
+	}, statsHandlerOpts...)
+}
+
+// GoGRPCPrometheusClientStatsHandler instantiates a client-side coordinator together with stats handlers that reproduce metrics of go-grpc-prometheus, e.g. grpc_client_handled_total.
+// It makes it possible to migrate from go-grpc-prometheus without changing dashboards or alerts.
+// Like in go-grpc-prometheus, calls are counted, no matter how many attempts (e.g. retries) they take,
+// as long as UnaryClientInterceptor and StreamClientInterceptor are installed. Otherwise, every attempt is counted separately.
+func GoGRPCPrometheusClientStatsHandler(opts ...ShareableOption) *StatsHandler {
+	collectorOpts, statsHandlerOpts := optionsSplit(opts...)
+
+	return NewStatsHandlerWithOptions([]StatsHandlerCollector{
+		NewClientStartedStatsHandler(NewClientStartedCounterVec(collectorOpts...), statsHandlerOpts...),
+		NewClientHandledStatsHandler(NewClientHandledCounterVec(collectorOpts...), statsHandlerOpts...),
+		NewClientMsgReceivedStatsHandler(NewClientMsgReceivedCounterVec(collectorOpts...), statsHandlerOpts...),
+		NewClientMsgSentStatsHandler(NewClientMsgSentCounterVec(collectorOpts...), statsHandlerOpts...),
+		NewClientHandlingSecondsStatsHandler(NewClientHandlingSecondsHistogramVec(collectorOpts...), statsHandlerOpts...),
+	}, statsHandlerOpts...)
+}
+
+// GoGRPCPrometheusServerStatsHandler instantiates a server-side coordinator together with stats handlers that reproduce metrics of go-grpc-prometheus, e.g. grpc_server_handled_total.
+// It makes it possible to migrate from go-grpc-prometheus without changing dashboards or alerts.
+// grpc_server_handling_seconds is always enabled, as if EnableHandlingTimeHistogram was called.
+func GoGRPCPrometheusServerStatsHandler(opts ...ShareableOption) *StatsHandler {
+	collectorOpts, statsHandlerOpts := optionsSplit(opts...)
+
+	return NewStatsHandlerWithOptions([]StatsHandlerCollector{
+		NewServerStartedStatsHandler(NewServerStartedCounterVec(collectorOpts...), statsHandlerOpts...),
+		NewServerHandledStatsHandler(NewServerHandledCounterVec(collectorOpts...), statsHandlerOpts...),
+		NewServerMsgReceivedStatsHandler(NewServerMsgReceivedCounterVec(collectorOpts...), statsHandlerOpts...),
+		NewServerMsgSentStatsHandler(NewServerMsgSentCounterVec(collectorOpts...), statsHandlerOpts...),
+		NewServerHandlingSecondsStatsHandler(NewServerHandlingSecondsHistogramVec(collectorOpts...), statsHandlerOpts...),
+	}, statsHandlerOpts...)
 }
 
 // TagRPC implements stats Handler interface.
 func (h *StatsHandler) TagRPC(ctx context.Context, inf *stats.RPCTagInfo) context.Context {
+	if h.options.methodFilter != nil && !h.options.methodFilter(inf.FullMethodName) {
+		if ctx.Value(coordinatorKey{h: h}) != nil {
+			// Labels inherited from another RPC (e.g. an incoming one that makes an outgoing call) must not be reused.
+			return context.WithValue(ctx, coordinatorKey{h: h}, nil)
+		}
+		return ctx
+	}
+
 	service, method := split(inf.FullMethodName)
+	fullMethod := strings.TrimPrefix(inf.FullMethodName, "/")
+	registered := true
+	if service == unknown || !h.registered(fullMethod) {
+		service, method, fullMethod = unknown, unknown, unknown
+		registered = false
+	}
 
-	ctx = context.WithValue(ctx, tagRPCKey, rpcTagLabels{
-		isFailFast:      strconv.FormatBool(inf.FailFast),
-		service:         service,
-		method:          method,
-		clientUserAgent: userAgentOnServerSide(ctx, inf),
-	})
+	tag := &rpcTagLabels{
+		isFailFast:          strconv.FormatBool(inf.FailFast),
+		service:             service,
+		method:              method,
+		clientUserAgent:     userAgentOnServerSide(ctx, inf),
+		fullMethod:          fullMethod,
+		target:              notAvailable,
+		registered:          registered,
+		receivedCompression: notAvailable,
+		sentCompression:     notAvailable,
+		rpcType:             notAvailable,
+	}
+	if h.options.customLabelsFn != nil {
+		tag.custom = fitLabelValues(h.options.customLabelsFn(ctx, inf), len(h.options.customLabels))
+	}
+	if len(h.options.metadataLabels) > 0 {
+		tag.metadata = metadataLabelValues(ctx, h.options.metadataLabels)
+	}
+	if mrk, ok := ctx.Value(callKey{h: h}).(*callMark); ok {
+		mrk.attempt(tag)
+		tag.target = mrk.target
+	}
+
+	ctx = context.WithValue(ctx, coordinatorKey{h: h}, tag)
+	ctx = context.WithValue(ctx, tagRPCKey, tag)
 
 	for _, c := range h.handlers {
 		ctx = c.TagRPC(ctx, inf)
@@ -84,8 +216,54 @@ func (h *StatsHandler) TagRPC(ctx context.Context, inf *stats.RPCTagInfo) contex
 	return ctx
 }
 
+// registered reports whether a method (e.g. package.Service/Method) is exposed by a server.
+// If the list of methods is not known, every method is considered registered.
+func (h *StatsHandler) registered(fullMethod string) bool {
+	rm := h.registeredMethods.Load()
+	if rm == nil {
+		return true
+	}
+	_, ok := (*rm)[fullMethod]
+	return ok
+}
+
+// RegisterServiceInfo makes the coordinator report methods not exposed by a given server (e.g. *grpc.Server) as unknown,
+// the same way StatsHandlerWithRegisteredMethods does.
+// It has to be called once all services are registered. It is safe to call it while the server is serving,
+// RPCs tagged before are reported the way they would be without it.
+func (h *StatsHandler) RegisterServiceInfo(provider ServiceInfoProvider) {
+	rm := newRegisteredMethods(serviceInfoMethods(provider))
+	h.registeredMethods.Store(&rm)
+}
+
 // HandleRPC implements stats Handler interface.
 func (h *StatsHandler) HandleRPC(ctx context.Context, sts stats.RPCStats) {
+	tag, _ := ctx.Value(coordinatorKey{h: h}).(*rpcTagLabels)
+	if tag == nil {
+		// Filtered out by TagRPC. The RPC still occupies a connection, stats handlers that keep track of connections have to know about it.
+		switch sts.(type) {
+		case *stats.Begin, *stats.End:
+			for _, c := range h.handlers {
+				if _, ok := c.(connectionTracker); ok {
+					c.HandleRPC(ctx, sts)
+				}
+			}
+		}
+		return
+	}
+	if current, _ := ctx.Value(tagRPCKey).(*rpcTagLabels); current != tag {
+		// Another coordinator tagged the RPC afterwards, stats handlers have to see labels of their own coordinator.
+		ctx = context.WithValue(ctx, tagRPCKey, tag)
+	}
+
+	switch pay := sts.(type) {
+	case *stats.InHeader:
+		tag.receivedCompression = compressor(pay.Compression)
+	case *stats.OutHeader:
+		tag.sentCompression = compressor(pay.Compression)
+	case *stats.Begin:
+		tag.rpcType = rpcType(pay)
+	}
 	for _, c := range h.handlers {
 		c.HandleRPC(ctx, sts)
 	}
@@ -106,6 +284,87 @@ func (h *StatsHandler) HandleConn(ctx context.Context, sts stats.ConnStats) {
 	}
 }
 
+// UnaryClientInterceptor returns an interceptor that makes it possible for stats handlers to observe calls, not only attempts.
+// grpc-go reports every attempt of a call (e.g. a retry) as a separate RPC.
+// Stats handlers that operate on calls, like ClientAttemptsPerCallStatsHandler, require it to be installed next to the coordinator.
+func (h *StatsHandler) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, mrk := h.withCallMark(ctx, cc)
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		h.handleCall(ctx, mrk, err)
+		return err
+	}
+}
+
+// StreamClientInterceptor is a streaming counterpart of UnaryClientInterceptor.
+// A call is considered finished once the stream returns an error (including io.EOF) or, for streams without server-side streaming, once the response is received.
+// A call that does not get that far, e.g. because it is abandoned, is considered finished once its context is done.
+func (h *StatsHandler) StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx, mrk := h.withCallMark(ctx, cc)
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			h.handleCall(ctx, mrk, err)
+			return nil, err
+		}
+		ccs := &callClientStream{
+			ClientStream:  cs,
+			serverStreams: desc.ServerStreams,
+			done: func(err error) {
+				h.handleCall(ctx, mrk, err)
+			},
+		}
+		ccs.stop = context.AfterFunc(ctx, func() {
+			// Not finish, the stop function may not be assigned yet and there is nothing to stop anyway.
+			ccs.once.Do(func() {
+				ccs.done(status.FromContextError(ctx.Err()).Err())
+			})
+		})
+		return ccs, nil
+	}
+}
+
+// handleCall passes a finished call to stats handlers that operate on calls.
+// The call is represented by stats.End, as if it was reported for the last attempt.
+func (h *StatsHandler) handleCall(ctx context.Context, mrk *callMark, err error) {
+	tag := mrk.tag.Load()
+	if tag == nil {
+		// No attempt was made, e.g. the call was canceled while waiting for a transport.
+		return
+	}
+
+	ctx = context.WithValue(ctx, tagRPCKey, tag)
+	end := &stats.End{
+		Client:    true,
+		BeginTime: mrk.begin,
+		EndTime:   time.Now(),
+		Error:     err,
+	}
+	for _, c := range h.handlers {
+		if ch, ok := c.(callStatsHandler); ok {
+			ch.handleCall(ctx, mrk.attempts.Load(), end)
+		}
+	}
+}
+
+// Preinitialize creates series of every method a server (e.g. *grpc.Server) exposes, so that they are reported as zero before the first RPC.
+// That way, functions like rate() or absent() work from the moment the server starts.
+// Only stats handlers of counters and gauges whose labels are known up front take part, e.g. ServerRequestsTotalStatsHandler.
+// Labels that depend on an RPC itself, like grpc_client_user_agent, rule out a stats handler.
+// Histograms are not preinitialized, as every status code would multiply the number of series by the number of buckets.
+// Methods rejected by StatsHandlerWithMethodFilter are skipped.
+// It has to be called once all services are registered.
+func (h *StatsHandler) Preinitialize(provider ServiceInfoProvider) {
+	if h.options.methodFilter != nil {
+		provider = filteredServiceInfo{provider: provider, filter: h.options.methodFilter}
+	}
+	for _, c := range h.handlers {
+		if p, ok := c.(preinitializer); ok {
+			p.Preinitialize(provider)
+		}
+	}
+}
+
 // Describe implements prometheus Collector interface.
 func (h *StatsHandler) Describe(in chan<- *prometheus.Desc) {
 	for _, c := range h.handlers {
@@ -123,6 +382,8 @@ func (h *StatsHandler) Collect(in chan<- prometheus.Metric) {
 type baseStatsHandler struct {
 	collector prometheus.Collector
 	options   statsHandlerOptions
+	// supportedLabels is a set of optional labels a stats handler is able to provide.
+	supportedLabels optionalLabels
 }
 
 // TagRPC implements stats Handler interface.
@@ -163,16 +424,112 @@ func (h *baseStatsHandler) HandleRPC(_ context.Context, _ stats.RPCStats) {
 // Describe implements prometheus Collector interface.
 func (h *baseStatsHandler) Describe(in chan<- *prometheus.Desc) {
 	h.collector.Describe(in)
+	for _, c := range h.options.methodCollectors {
+		c.Describe(in)
+	}
 }
 
 // Collect implements prometheus Collector interface.
 func (h *baseStatsHandler) Collect(in chan<- prometheus.Metric) {
 	h.collector.Collect(in)
+	for _, c := range h.options.methodCollectors {
+		c.Collect(in)
+	}
+}
+
+// observe records a value, together with an exemplar if a stats handler is configured to do so.
+// If the method of an RPC has its own vector, the value is recorded there instead.
+func (h *baseStatsHandler) observe(ctx context.Context, vec prometheus.ObserverVec, lvs []string, v float64) {
+	if len(h.options.methodVecs) > 0 {
+		if tag, ok := ctx.Value(tagRPCKey).(*rpcTagLabels); ok {
+			if mvec, ok := h.options.methodVecs[tag.fullMethod]; ok {
+				vec = mvec
+			}
+		}
+	}
+
+	obs := vec.WithLabelValues(lvs...)
+	if eo, ok := obs.(prometheus.ExemplarObserver); ok {
+		if lbs := h.exemplar(ctx); lbs != nil {
+			eo.ObserveWithExemplar(v, lbs)
+			return
+		}
+	}
+	obs.Observe(v)
+}
+
+// inc increments a counter, together with an exemplar if a stats handler is configured to do so.
+func (h *baseStatsHandler) inc(ctx context.Context, cnt prometheus.Counter) {
+	if ea, ok := cnt.(prometheus.ExemplarAdder); ok {
+		if lbs := h.exemplar(ctx); lbs != nil {
+			ea.AddWithExemplar(1, lbs)
+			return
+		}
+	}
+	cnt.Inc()
+}
+
+func (h *baseStatsHandler) exemplar(ctx context.Context) prometheus.Labels {
+	if h.options.exemplarFn == nil {
+		return nil
+	}
+	if lbs := h.options.exemplarFn(ctx); len(lbs) > 0 && validExemplar(lbs) {
+		return lbs
+	}
+	return nil
+}
+
+// labelValues returns label values produced by handleRPCLabelFn followed by values of enabled optional labels, custom labels and metadata labels.
+func (h *baseStatsHandler) labelValues(ctx context.Context, stat stats.RPCStats) []string {
+	values := h.options.handleRPCLabelFn(ctx, stat)
+	if enabled := h.options.optionalLabels & h.supportedLabels; enabled != 0 {
+		values = append(values, enabled.values(ctx, stat)...)
+	}
+	if n := len(h.options.customLabels); n > 0 {
+		// The coordinator could have been allocated without custom labels, see NewStatsHandlerWithOptions.
+		values = append(values, fitLabelValues(ctx.Value(tagRPCKey).(*rpcTagLabels).custom, n)...)
+	}
+	if n := len(h.options.metadataLabels); n > 0 {
+		values = append(values, fitLabelValues(ctx.Value(tagRPCKey).(*rpcTagLabels).metadata, n)...)
+	}
+	return values
+}
+
+// preinitializedLabels returns label values of series that given stats produce, for every method a server exposes.
+// Stats handlers use them to create series at zero, see StatsHandler.Preinitialize.
+// It returns nothing if labels are not known up front, e.g. because of a custom label function or optional labels that depend on a payload.
+func (h *baseStatsHandler) preinitializedLabels(provider ServiceInfoProvider, sts ...stats.RPCStats) [][]string {
+	if h.options.customHandleRPCLabelFn || len(h.options.customLabels) > 0 || len(h.options.metadataLabels) > 0 ||
+		h.options.optionalLabels&h.supportedLabels&^optionalLabelType != 0 {
+		return nil
+	}
+
+	var res [][]string
+	for service, info := range provider.GetServiceInfo() {
+		for _, m := range info.Methods {
+			ctx := context.WithValue(context.Background(), tagRPCKey, &rpcTagLabels{
+				isFailFast:          strconv.FormatBool(false),
+				service:             service,
+				method:              m.Name,
+				clientUserAgent:     notAvailable,
+				fullMethod:          service + "/" + m.Name,
+				target:              notAvailable,
+				registered:          true,
+				receivedCompression: notAvailable,
+				sentCompression:     notAvailable,
+				rpcType:             rpcType(&stats.Begin{IsClientStream: m.IsClientStream, IsServerStream: m.IsServerStream}),
+			})
+			for _, stat := range sts {
+				res = append(res, h.labelValues(ctx, stat))
+			}
+		}
+	}
+	return res
 }
 
 func (h *baseStatsHandler) applyOpts(opts ...StatsHandlerOption) {
 	for _, opt := range opts {
-		opt.apply(&h.options)
+		opt.applyStatsHandler(&h.options)
 	}
 }
 
@@ -183,15 +540,134 @@ func optionsSplit(opts ...ShareableOption) ([]CollectorOption, []StatsHandlerOpt
 	)
 
 	for _, opt := range opts {
-		switch val := opt.(type) {
-		case StatsHandlerOption:
-			statsHandlerOpts = append(statsHandlerOpts, val)
-		case CollectorOption:
-			collectorOpts = append(collectorOpts, val)
-		default:
+		statsHandlerOpt, isStatsHandlerOpt := opt.(StatsHandlerOption)
+		collectorOpt, isCollectorOpt := opt.(CollectorOption)
+		if !isStatsHandlerOpt && !isCollectorOpt {
 			panic(fmt.Sprintf("shareable option does not implement any known type: %T", opt))
 		}
+		// ShareableLabelOption goes to both.
+		if isStatsHandlerOpt {
+			statsHandlerOpts = append(statsHandlerOpts, statsHandlerOpt)
+		}
+		if isCollectorOpt {
+			collectorOpts = append(collectorOpts, collectorOpt)
+		}
 	}
 
 	return collectorOpts, statsHandlerOpts
 }
+
+// preinitializer is implemented by stats handlers able to create their series up front.
+// See StatsHandler.Preinitialize.
+type preinitializer interface {
+	Preinitialize(provider ServiceInfoProvider)
+}
+
+// everyCodeEnd returns stats.End of an incoming RPC for every status code.
+func everyCodeEnd() []stats.RPCStats {
+	sts := make([]stats.RPCStats, 0, len(statusCodeNames))
+	for c := range statusCodeNames {
+		sts = append(sts, &stats.End{Error: status.Error(codes.Code(c), "")})
+	}
+	return sts
+}
+
+// coordinatorKey is a key a coordinator stores labels of an RPC under.
+// Every coordinator has its own, so that multiple coordinators installed on the same server or client do not share labels.
+// Stats handlers read labels of their coordinator using tagRPCKey, see StatsHandler.HandleRPC.
+type coordinatorKey struct {
+	h *StatsHandler
+}
+
+// connectionTracker is implemented by stats handlers that attribute RPCs to connections, e.g. ServerIdleConnectionsStatsHandler.
+// They depend on the context of a connection only, and see the beginning and the end of RPCs skipped by StatsHandlerWithMethodFilter.
+type connectionTracker interface {
+	tracksConnections()
+}
+
+// callStatsHandler is implemented by stats handlers that operate on calls.
+// See StatsHandler.UnaryClientInterceptor.
+type callStatsHandler interface {
+	handleCall(ctx context.Context, attempts int64, stat *stats.End)
+}
+
+// callKey is a key a call mark is stored under.
+// It is bound to the coordinator whose interceptor made the mark, so that other coordinators do not count attempts of the call.
+type callKey struct {
+	h *StatsHandler
+}
+
+// callMark keeps track of attempts made within a single call.
+type callMark struct {
+	begin    time.Time
+	target   string
+	attempts atomic.Int64
+	// tag holds labels of the most recent attempt.
+	tag atomic.Pointer[rpcTagLabels]
+}
+
+func (h *StatsHandler) withCallMark(ctx context.Context, cc *grpc.ClientConn) (context.Context, *callMark) {
+	mrk := &callMark{
+		begin:  time.Now(),
+		target: notAvailable,
+	}
+	if cc != nil {
+		mrk.target = cc.CanonicalTarget()
+	}
+	return context.WithValue(ctx, callKey{h: h}, mrk), mrk
+}
+
+func (m *callMark) attempt(tag *rpcTagLabels) {
+	tag.attempt = m.attempts.Add(1)
+	m.tag.Store(tag)
+}
+
+// callClientStream reports the end of a streaming call once it is known.
+type callClientStream struct {
+	grpc.ClientStream
+	serverStreams bool
+	once          sync.Once
+	done          func(error)
+	// stop unregisters the function that finishes the call once its context is done.
+	stop func() bool
+}
+
+// SendMsg implements grpc ClientStream interface.
+// io.EOF means that the stream is terminated, its status is returned by RecvMsg.
+func (s *callClientStream) SendMsg(m any) error {
+	err := s.ClientStream.SendMsg(m)
+	if err != nil && !errors.Is(err, io.EOF) {
+		s.finish(err)
+	}
+	return err
+}
+
+// Header implements grpc ClientStream interface.
+func (s *callClientStream) Header() (metadata.MD, error) {
+	md, err := s.ClientStream.Header()
+	if err != nil {
+		s.finish(err)
+	}
+	return md, err
+}
+
+// RecvMsg implements grpc ClientStream interface.
+func (s *callClientStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	switch {
+	case errors.Is(err, io.EOF):
+		s.finish(nil)
+	case err != nil:
+		s.finish(err)
+	case !s.serverStreams:
+		s.finish(nil)
+	}
+	return err
+}
+
+func (s *callClientStream) finish(err error) {
+	s.once.Do(func() {
+		s.stop()
+		s.done(err)
+	})
+}
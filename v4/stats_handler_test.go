@@ -2,16 +2,22 @@ package promgrpc_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net"
+	"reflect"
 	"testing"
 	"time"
 
+	"github.com/piotrkowalczuk/promgrpc/v4"
 	"github.com/piotrkowalczuk/promgrpc/v4/internal/testutil"
 	"github.com/piotrkowalczuk/promgrpc/v4/pb/private/test"
 	"github.com/prometheus/client_golang/prometheus"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
 )
 
 func TestStatsHandler(t *testing.T) {
@@ -142,6 +148,347 @@ func TestStatsHandler(t *testing.T) {
 	assert(t, exp)
 }
 
+func TestA66StatsHandler(t *testing.T) {
+	t.Parallel()
+
+	lis := listener(t)
+
+	ssh := promgrpc.A66ServerStatsHandler()
+	csh := promgrpc.A66ClientStatsHandler()
+	srv := grpc.NewServer(grpc.StatsHandler(ssh))
+	test.RegisterTestServiceServer(srv, newDemoServer())
+	ssh.RegisterServiceInfo(srv)
+	go func() {
+		if err := srv.Serve(lis); !errors.Is(err, grpc.ErrServerStopped) && err != nil {
+			t.Error(err)
+		}
+	}()
+	defer srv.GracefulStop()
+
+	cli, err := grpc.NewClient(lis.Addr().String(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(csh),
+		grpc.WithUnaryInterceptor(csh.UnaryClientInterceptor()),
+		grpc.WithStreamInterceptor(csh.StreamClientInterceptor()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cli.Close()
+
+	reg := prometheus.NewRegistry()
+	registerCollector(t, reg, ssh)
+	registerCollector(t, reg, csh)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rpc := test.NewTestServiceClient(cli)
+	for i := 0; i < 10; i++ {
+		if _, err := rpc.Unary(ctx, &test.Request{Value: "example"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dimensions := map[string]string{
+		"grpc_method": "piotrkowalczuk.promgrpc.v4.test.TestService/Unary",
+		"grpc_status": "OK",
+		"grpc_target": cli.CanonicalTarget(),
+	}
+	testutil.AssertMetricValue(t, reg, "grpc_client_attempt_started_total", 10)
+	testutil.AssertMetricValue(t, reg, "grpc_client_attempt_duration_seconds_count", 10)
+	testutil.AssertMetricDimensions(t, reg, "grpc_client_attempt_duration_seconds_count", dimensions)
+	testutil.AssertMetricValue(t, reg, "grpc_client_call_duration_seconds_count", 10)
+	testutil.AssertMetricDimensions(t, reg, "grpc_client_call_duration_seconds_count", dimensions)
+	testutil.AssertMetricValue(t, reg, "grpc_server_call_started_total", 10)
+	testutil.AssertMetricDimensions(t, reg, "grpc_server_call_started_total", map[string]string{
+		"grpc_method": "piotrkowalczuk.promgrpc.v4.test.TestService/Unary",
+	})
+}
+
+func TestGoGRPCPrometheusStatsHandler(t *testing.T) {
+	t.Parallel()
+
+	lis := listener(t)
+
+	ssh := promgrpc.GoGRPCPrometheusServerStatsHandler()
+	csh := promgrpc.GoGRPCPrometheusClientStatsHandler()
+	srv := grpc.NewServer(grpc.StatsHandler(ssh))
+	test.RegisterTestServiceServer(srv, newDemoServer())
+	go func() {
+		if err := srv.Serve(lis); !errors.Is(err, grpc.ErrServerStopped) && err != nil {
+			t.Error(err)
+		}
+	}()
+	defer srv.GracefulStop()
+
+	cli, err := grpc.NewClient(lis.Addr().String(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(csh),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cli.Close()
+
+	reg := prometheus.NewRegistry()
+	registerCollector(t, reg, ssh)
+	registerCollector(t, reg, csh)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rpc := test.NewTestServiceClient(cli)
+	for i := 0; i < 10; i++ {
+		if _, err := rpc.Unary(ctx, &test.Request{Value: "example"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dimensions := map[string]string{
+		"grpc_code":    "OK",
+		"grpc_method":  "Unary",
+		"grpc_service": "piotrkowalczuk.promgrpc.v4.test.TestService",
+		"grpc_type":    "unary",
+	}
+	for _, name := range []string{"grpc_client_handled_total", "grpc_server_handled_total", "grpc_client_handling_seconds_count", "grpc_server_handling_seconds_count"} {
+		testutil.AssertMetricValue(t, reg, name, 10)
+		testutil.AssertMetricDimensions(t, reg, name, dimensions)
+	}
+	for _, name := range []string{"grpc_client_started_total", "grpc_server_started_total", "grpc_client_msg_sent_total", "grpc_server_msg_received_total"} {
+		testutil.AssertMetricValue(t, reg, name, 10)
+	}
+}
+
+func TestStatsHandler_RegisterServiceInfo(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	ssh := promgrpc.ServerStatsHandler()
+	srv := grpc.NewServer(grpc.StatsHandler(ssh))
+	test.RegisterTestServiceServer(srv, newDemoServer())
+	ssh.RegisterServiceInfo(srv)
+
+	reg := prometheus.NewRegistry()
+	registerCollector(t, reg, ssh)
+
+	for _, method := range []string{
+		test.TestService_Unary_FullMethodName,
+		"/piotrkowalczuk.promgrpc.v4.test.TestService/Random",
+		"/random.Service/Method",
+		"malformed",
+		"piotrkowalczuk.promgrpc.v4.test.TestService/Unary",
+	} {
+		sctx := ssh.TagRPC(ctx, &stats.RPCTagInfo{FullMethodName: method})
+		ssh.HandleRPC(sctx, &stats.Begin{})
+		ssh.HandleRPC(sctx, &stats.End{})
+	}
+
+	testutil.AssertMetricValue(t, reg, "grpc_server_responses_sent_total", 5)
+	testutil.AssertMetricDimensions(t, reg, "grpc_server_responses_sent_total", map[string]string{
+		"grpc_service": "piotrkowalczuk.promgrpc.v4.test.TestService",
+		"grpc_method":  "Unary",
+	})
+	testutil.AssertMetricDimensions(t, reg, "grpc_server_responses_sent_total", map[string]string{
+		"grpc_service": "unknown",
+		"grpc_method":  "unknown",
+	})
+
+	mf, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range mf {
+		if m.GetName() == "grpc_server_responses_sent_total" && len(m.GetMetric()) != 2 {
+			t.Fatalf("unexpected number of series, expected 2 but got %d", len(m.GetMetric()))
+		}
+	}
+}
+
+func TestStatsHandler_RegisterServiceInfo_whileServing(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	ssh := promgrpc.A66ServerStatsHandler()
+	srv := grpc.NewServer(grpc.StatsHandler(ssh))
+	test.RegisterTestServiceServer(srv, newDemoServer())
+
+	reg := prometheus.NewRegistry()
+	registerCollector(t, reg, ssh)
+
+	start := func(method string) {
+		sctx := ssh.TagRPC(ctx, &stats.RPCTagInfo{FullMethodName: method})
+		ssh.HandleRPC(sctx, &stats.Begin{})
+	}
+
+	// Without registered methods, every method is reported as it is.
+	start("/random.Service/Method")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			start(test.TestService_Unary_FullMethodName)
+		}
+	}()
+	ssh.RegisterServiceInfo(srv)
+	<-done
+
+	start("/random.Service/Method")
+
+	testutil.AssertMetricValue(t, reg, "grpc_server_call_started_total", 102)
+	for _, method := range []string{"random.Service/Method", "other", "piotrkowalczuk.promgrpc.v4.test.TestService/Unary"} {
+		testutil.AssertMetricDimensions(t, reg, "grpc_server_call_started_total", map[string]string{
+			"grpc_method": method,
+		})
+	}
+}
+
+func TestStatsHandler_customLabels(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	ssh := promgrpc.ServerStatsHandler(promgrpc.WithCustomLabels(func(ctx context.Context, _ *stats.RPCTagInfo) []string {
+		if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get("x-tenant")) == 1 {
+			return []string{md.Get("x-tenant")[0]}
+		}
+		return []string{"none"}
+	}, "tenant"))
+	reg := prometheus.NewRegistry()
+	registerCollector(t, reg, ssh)
+
+	ctx = ssh.TagConn(ctx, &stats.ConnTagInfo{
+		RemoteAddr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 5000},
+		LocalAddr:  &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 6000},
+	})
+	ssh.HandleConn(ctx, &stats.ConnBegin{})
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-tenant", "acme"))
+	ctx = ssh.TagRPC(ctx, &stats.RPCTagInfo{FullMethodName: "/service/Method"})
+	ssh.HandleRPC(ctx, &stats.Begin{})
+	ssh.HandleRPC(ctx, &stats.InPayload{Length: 10})
+	ssh.HandleRPC(ctx, &stats.OutPayload{Length: 20})
+	ssh.HandleRPC(ctx, &stats.End{})
+
+	mf, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mf) != 9 {
+		t.Fatalf("unexpected number of metrics, expected 9 but got %d", len(mf))
+	}
+	for _, m := range mf {
+		if m.GetName() == "grpc_server_connections" {
+			continue
+		}
+	Metrics:
+		for _, metric := range m.GetMetric() {
+			for _, lp := range metric.GetLabel() {
+				if lp.GetName() == "tenant" && lp.GetValue() == "acme" {
+					continue Metrics
+				}
+			}
+			t.Errorf("%s: tenant label is missing: %v", m.GetName(), metric.GetLabel())
+		}
+	}
+}
+
+func TestStatsHandler_customLabels_mismatch(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Both coordinators installed on the same server, one of them with labels its function does not fully provide.
+	custom := promgrpc.ServerStatsHandler(promgrpc.WithCustomLabels(func(context.Context, *stats.RPCTagInfo) []string {
+		return []string{"acme"}
+	}, "tenant", "region"))
+	plain := promgrpc.ServerStatsHandler()
+
+	for _, coordinators := range [][]*promgrpc.StatsHandler{{custom, plain}, {plain, custom}} {
+		sctx := ctx
+		for _, c := range coordinators {
+			sctx = c.TagRPC(sctx, &stats.RPCTagInfo{FullMethodName: "/service/Method"})
+		}
+		for _, sts := range []stats.RPCStats{&stats.Begin{}, &stats.End{}} {
+			for _, c := range coordinators {
+				c.HandleRPC(sctx, sts)
+			}
+		}
+	}
+
+	reg := prometheus.NewRegistry()
+	registerCollector(t, reg, custom)
+	mf, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range mf {
+		if m.GetName() != "grpc_server_requests_received_total" {
+			continue
+		}
+		got := make(map[string]string)
+		for _, lp := range m.GetMetric()[0].GetLabel() {
+			got[lp.GetName()] = lp.GetValue()
+		}
+		if got["tenant"] != "acme" || got["region"] != "n/a" {
+			t.Fatalf("wrong custom labels: %v", got)
+		}
+		if n := m.GetMetric()[0].GetCounter().GetValue(); len(m.GetMetric()) != 1 || n != 2 {
+			t.Fatalf("unexpected series, expected one with 2 requests but got %d with %g", len(m.GetMetric()), n)
+		}
+		return
+	}
+	t.Fatal("grpc_server_requests_received_total not found")
+}
+
+func TestStatsHandler_metadataLabels(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tier := promgrpc.MetadataLabel{Key: "X-Tenant-Tier", Name: "tenant_tier", Values: []string{"gold", "silver"}}
+	ssh := promgrpc.ServerStatsHandler(promgrpc.WithMetadataLabels(tier))
+	reg := prometheus.NewRegistry()
+	registerCollector(t, reg, ssh)
+
+	ctx = ssh.TagConn(ctx, &stats.ConnTagInfo{
+		RemoteAddr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 5000},
+		LocalAddr:  &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 6000},
+	})
+	ssh.HandleConn(ctx, &stats.ConnBegin{})
+	for _, md := range []metadata.MD{
+		metadata.Pairs("x-tenant-tier", "gold"),
+		metadata.Pairs("x-tenant-tier", "platinum"),
+		metadata.Pairs("x-tenant-tier", "gold", "x-tenant-tier", "silver"),
+		metadata.Pairs(),
+	} {
+		ctx := ssh.TagRPC(metadata.NewIncomingContext(ctx, md), &stats.RPCTagInfo{FullMethodName: "/service/Method"})
+		ssh.HandleRPC(ctx, &stats.Begin{})
+		ssh.HandleRPC(ctx, &stats.End{})
+	}
+
+	mf, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range mf {
+		if m.GetName() != "grpc_server_requests_received_total" {
+			continue
+		}
+		got := make(map[string]float64)
+		for _, metric := range m.GetMetric() {
+			for _, lp := range metric.GetLabel() {
+				if lp.GetName() == "tenant_tier" {
+					got[lp.GetValue()] += metric.GetCounter().GetValue()
+				}
+			}
+		}
+		expected := map[string]float64{"gold": 1, "other": 2, "n/a": 1}
+		if !reflect.DeepEqual(got, expected) {
+			t.Fatalf("wrong tenant_tier values, expected %v but got %v", expected, got)
+		}
+		return
+	}
+	t.Fatal("grpc_server_requests_received_total not found")
+}
+
 func listener(t *testing.T) net.Listener {
 	lis, err := net.Listen("tcp", "127.0.0.1:0")
 	if err != nil {
@@ -0,0 +1,343 @@
+package promgrpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+)
+
+// Labels and values used by promgrpc v3.
+const (
+	v3LabelService    = "service"
+	v3LabelHandler    = "handler"
+	v3LabelCode       = "code"
+	v3LabelType       = "type"
+	v3LabelUserAgent  = "user_agent"
+	v3LabelFailFast   = "fail_fast"
+	v3LabelRemoteAddr = "remote_addr"
+	v3LabelLocalAddr  = "local_addr"
+
+	v3RPCTypeBidiStream = "bidirectional_stream"
+	v3UserAgentNotSet   = "not-set"
+)
+
+// V3ClientStatsHandler instantiates a client-side coordinator together with stats handlers that emit metrics named and labeled the way promgrpc v3 did,
+// e.g. grpc_client_requests_total{service,handler,code,type}.
+// Unlike v3, it does not track reconnects and counts every attempt of a call (e.g. a retry) separately.
+func V3ClientStatsHandler(opts ...ShareableOption) *StatsHandler {
+	collectorOpts, statsHandlerOpts := optionsSplit(opts...)
+
+	return NewStatsHandlerWithOptions(v3ClientStatsHandlers(true, collectorOpts, statsHandlerOpts), statsHandlerOpts...)
+}
+
+// V3ServerStatsHandler instantiates a server-side coordinator together with stats handlers that emit metrics named and labeled the way promgrpc v3 did,
+// e.g. grpc_server_requests_total{service,handler,code,type,user_agent}.
+func V3ServerStatsHandler(opts ...ShareableOption) *StatsHandler {
+	collectorOpts, statsHandlerOpts := optionsSplit(opts...)
+
+	return NewStatsHandlerWithOptions(v3ServerStatsHandlers(true, collectorOpts, statsHandlerOpts), statsHandlerOpts...)
+}
+
+// MigrationClientStatsHandler instantiates a client-side coordinator that emits both, metrics of ClientStatsHandler and V3ClientStatsHandler.
+// It is meant to be used for a transition period, while dashboards and alerts are migrated from v3 to v4 series.
+// grpc_client_connections is the only metric that v3 and v4 share the name of, it is emitted once, with v4 labels.
+func MigrationClientStatsHandler(opts ...ShareableOption) *StatsHandler {
+	collectorOpts, statsHandlerOpts := optionsSplit(opts...)
+
+	return NewStatsHandlerWithOptions(append(
+		clientStatsHandlers(collectorOpts, statsHandlerOpts),
+		v3ClientStatsHandlers(false, collectorOpts, statsHandlerOpts)...,
+	), statsHandlerOpts...)
+}
+
+// MigrationServerStatsHandler instantiates a server-side coordinator that emits both, metrics of ServerStatsHandler and V3ServerStatsHandler.
+// It is meant to be used for a transition period, while dashboards and alerts are migrated from v3 to v4 series.
+// grpc_server_connections is the only metric that v3 and v4 share the name of, it is emitted once, with v4 labels.
+func MigrationServerStatsHandler(opts ...ShareableOption) *StatsHandler {
+	collectorOpts, statsHandlerOpts := optionsSplit(opts...)
+
+	return NewStatsHandlerWithOptions(append(
+		serverStatsHandlers(collectorOpts, statsHandlerOpts),
+		v3ServerStatsHandlers(false, collectorOpts, statsHandlerOpts)...,
+	), statsHandlerOpts...)
+}
+
+// v3ClientStatsHandlers builds v3 metrics on top of v4 stats handlers, by replacing their collectors and label functions.
+func v3ClientStatsHandlers(connections bool, collectorOpts []CollectorOption, statsHandlerOpts []StatsHandlerOption) []StatsHandlerCollector {
+	var (
+		requestLabels  = []string{v3LabelService, v3LabelHandler, v3LabelCode, v3LabelType}
+		messageLabels  = []string{v3LabelService, v3LabelHandler}
+		inFlightLabels = []string{v3LabelFailFast, v3LabelHandler, v3LabelService}
+	)
+
+	handlers := []StatsHandlerCollector{
+		NewClientRequestsInFlightStatsHandler(
+			newV3GaugeVec("client", "requests", "Number of currently processed client side rpc requests.", inFlightLabels, collectorOpts...),
+			v3StatsHandlerOpts(statsHandlerOpts, v3ClientInFlightLabels)...,
+		),
+		NewClientResponsesTotalStatsHandler(
+			newV3CounterVec("client", "requests_total", "Total number of RPC requests made by client.", requestLabels, collectorOpts...),
+			v3StatsHandlerOpts(statsHandlerOpts, v3ClientRequestLabels)...,
+		),
+		NewClientRequestDurationStatsHandler(
+			newV3HistogramVec("client", "request_duration_seconds", "The RPC request latencies in seconds on client side.", requestLabels, collectorOpts...),
+			v3StatsHandlerOpts(statsHandlerOpts, v3ClientRequestLabels)...,
+		),
+		NewClientMessagesReceivedTotalStatsHandler(
+			newV3CounterVec("client", "received_messages_total", "Total number of RPC messages received.", messageLabels, collectorOpts...),
+			v3StatsHandlerOpts(statsHandlerOpts, v3ClientMessageLabels)...,
+		),
+		NewClientMessagesSentTotalStatsHandler(
+			newV3CounterVec("client", "send_messages_total", "Total number of RPC messages send.", messageLabels, collectorOpts...),
+			v3StatsHandlerOpts(statsHandlerOpts, v3ClientMessageLabels)...,
+		),
+		newV3ErrorsTotalStatsHandler(
+			newV3CounterVec("client", "errors_total", "Total number of errors that happen during RPC calls.", requestLabels, collectorOpts...),
+			true,
+			v3ClientRequestLabels,
+		),
+	}
+	if connections {
+		handlers = append(handlers, NewClientConnectionsStatsHandler(
+			newV3GaugeVec("client", "connections", "Number of currently opened client side connections.", []string{v3LabelRemoteAddr, v3LabelLocalAddr}, collectorOpts...),
+		))
+	}
+	return handlers
+}
+
+// v3ServerStatsHandlers builds v3 metrics on top of v4 stats handlers, by replacing their collectors and label functions.
+func v3ServerStatsHandlers(connections bool, collectorOpts []CollectorOption, statsHandlerOpts []StatsHandlerOption) []StatsHandlerCollector {
+	var (
+		requestLabels  = []string{v3LabelService, v3LabelHandler, v3LabelCode, v3LabelType, v3LabelUserAgent}
+		messageLabels  = []string{v3LabelService, v3LabelHandler, v3LabelUserAgent}
+		inFlightLabels = []string{v3LabelFailFast, v3LabelHandler, v3LabelService, v3LabelUserAgent}
+	)
+
+	handlers := []StatsHandlerCollector{
+		NewServerRequestsInFlightStatsHandler(
+			newV3GaugeVec("server", "requests", "Number of currently processed server side rpc requests.", inFlightLabels, collectorOpts...),
+			v3StatsHandlerOpts(statsHandlerOpts, v3ServerInFlightLabels)...,
+		),
+		NewServerResponsesTotalStatsHandler(
+			newV3CounterVec("server", "requests_total", "Total number of RPC requests received by server.", requestLabels, collectorOpts...),
+			v3StatsHandlerOpts(statsHandlerOpts, v3ServerRequestLabels)...,
+		),
+		NewServerRequestDurationStatsHandler(
+			newV3HistogramVec("server", "request_duration_seconds", "The RPC request latencies in seconds on server side.", requestLabels, collectorOpts...),
+			v3StatsHandlerOpts(statsHandlerOpts, v3ServerRequestLabels)...,
+		),
+		NewServerMessagesReceivedTotalStatsHandler(
+			newV3CounterVec("server", "received_messages_total", "Total number of RPC messages received by server.", messageLabels, collectorOpts...),
+			v3StatsHandlerOpts(statsHandlerOpts, v3ServerMessageLabels)...,
+		),
+		NewServerMessagesSentTotalStatsHandler(
+			newV3CounterVec("server", "send_messages_total", "Total number of RPC messages send by server.", messageLabels, collectorOpts...),
+			v3StatsHandlerOpts(statsHandlerOpts, v3ServerMessageLabels)...,
+		),
+		newV3ErrorsTotalStatsHandler(
+			newV3CounterVec("server", "errors_total", "Total number of errors that happen during RPC calles on server side.", requestLabels, collectorOpts...),
+			false,
+			v3ServerRequestLabels,
+		),
+	}
+	if connections {
+		handlers = append(handlers, newV3ServerConnectionsStatsHandler(
+			newV3GaugeVec("server", "connections", "Number of currently opened server side connections.", []string{v3LabelRemoteAddr, v3LabelLocalAddr, v3LabelUserAgent}, collectorOpts...),
+		))
+	}
+	return handlers
+}
+
+// v3StatsHandlerOpts appends an option that sets label function and disables optional labels, v3 collectors know nothing about.
+// It goes last, so it cannot be overridden.
+func v3StatsHandlerOpts(opts []StatsHandlerOption, fn HandleRPCLabelFunc) []StatsHandlerOption {
+	return append(opts[:len(opts):len(opts)], newFuncStatsHandlerOption(func(o *statsHandlerOptions) {
+		o.handleRPCLabelFn = fn
+		o.customHandleRPCLabelFn = true
+		o.customLabelsFn = nil
+		o.customLabels = nil
+		o.metadataLabels = nil
+		o.optionalLabels = 0
+	}))
+}
+
+// v3ErrorsTotalStatsHandler counts RPCs that finished with a status other than OK.
+// v4 has no equivalent, an error rate is derived from responses total.
+type v3ErrorsTotalStatsHandler struct {
+	baseStatsHandler
+	vec *prometheus.CounterVec
+}
+
+func newV3ErrorsTotalStatsHandler(vec *prometheus.CounterVec, client bool, fn HandleRPCLabelFunc) *v3ErrorsTotalStatsHandler {
+	return &v3ErrorsTotalStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+			options: statsHandlerOptions{
+				client:           client,
+				handleRPCLabelFn: fn,
+			},
+		},
+		vec: vec,
+	}
+}
+
+// HandleRPC implements stats Handler interface.
+func (h *v3ErrorsTotalStatsHandler) HandleRPC(ctx context.Context, stat stats.RPCStats) {
+	if end, ok := stat.(*stats.End); ok && stat.IsClient() == h.options.client && status.Code(end.Error) != codes.OK {
+		h.vec.WithLabelValues(h.labelValues(ctx, stat)...).Inc()
+	}
+}
+
+// v3ServerConnectionsStatsHandler counts open connections the same way ServerConnectionsStatsHandler does,
+// but reports a missing user agent the way v3 did.
+type v3ServerConnectionsStatsHandler struct {
+	baseStatsHandler
+	vec *prometheus.GaugeVec
+}
+
+func newV3ServerConnectionsStatsHandler(vec *prometheus.GaugeVec) *v3ServerConnectionsStatsHandler {
+	return &v3ServerConnectionsStatsHandler{
+		baseStatsHandler: baseStatsHandler{
+			collector: vec,
+		},
+		vec: vec,
+	}
+}
+
+// HandleConn implements stats Handler interface.
+func (h *v3ServerConnectionsStatsHandler) HandleConn(ctx context.Context, stat stats.ConnStats) {
+	if stat.IsClient() {
+		return
+	}
+	switch stat.(type) {
+	case *stats.ConnBegin:
+		h.vec.WithLabelValues(v3ServerConnectionLabels(ctx)...).Inc()
+	case *stats.ConnEnd:
+		h.vec.WithLabelValues(v3ServerConnectionLabels(ctx)...).Dec()
+	}
+}
+
+func v3ServerConnectionLabels(ctx context.Context) []string {
+	tag := ctx.Value(tagConnKey).(connTagLabels)
+	return []string{
+		tag.remoteAddr,
+		tag.localAddr,
+		v3UserAgent(tag.clientUserAgent),
+	}
+}
+
+func v3ClientRequestLabels(ctx context.Context, stat stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	return []string{
+		tag.service,
+		tag.method,
+		status.Code(stat.(*stats.End).Error).String(),
+		v3RPCType(tag.rpcType),
+	}
+}
+
+func v3ClientMessageLabels(ctx context.Context, _ stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	return []string{
+		tag.service,
+		tag.method,
+	}
+}
+
+func v3ClientInFlightLabels(ctx context.Context, _ stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	return []string{
+		tag.isFailFast,
+		tag.method,
+		tag.service,
+	}
+}
+
+func v3ServerRequestLabels(ctx context.Context, stat stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	return []string{
+		tag.service,
+		tag.method,
+		status.Code(stat.(*stats.End).Error).String(),
+		v3RPCType(tag.rpcType),
+		v3UserAgent(tag.clientUserAgent),
+	}
+}
+
+func v3ServerMessageLabels(ctx context.Context, _ stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	return []string{
+		tag.service,
+		tag.method,
+		v3UserAgent(tag.clientUserAgent),
+	}
+}
+
+func v3ServerInFlightLabels(ctx context.Context, _ stats.RPCStats) []string {
+	tag := ctx.Value(tagRPCKey).(*rpcTagLabels)
+	return []string{
+		tag.isFailFast,
+		tag.method,
+		tag.service,
+		v3UserAgent(tag.clientUserAgent),
+	}
+}
+
+// v3RPCType converts an RPC type into its v3 counterpart, where the only difference is the naming of bidirectional streams.
+func v3RPCType(typ string) string {
+	if typ == rpcTypeBidiStream {
+		return v3RPCTypeBidiStream
+	}
+	return typ
+}
+
+func v3UserAgent(ua string) string {
+	if ua == notAvailable {
+		return v3UserAgentNotSet
+	}
+	return ua
+}
+
+func newV3CounterVec(sub, name, help string, labels []string, opts ...CollectorOption) *prometheus.CounterVec {
+	prototype := prometheus.Opts{
+		Namespace: namespace,
+		Subsystem: strings.ToLower(sub),
+		Name:      name,
+		Help:      help,
+	}
+	return prometheus.NewCounterVec(
+		prometheus.CounterOpts(applyCollectorOptions(prototype, opts...)),
+		labels,
+	)
+}
+
+func newV3GaugeVec(sub, name, help string, labels []string, opts ...CollectorOption) *prometheus.GaugeVec {
+	prototype := prometheus.Opts{
+		Namespace: namespace,
+		Subsystem: strings.ToLower(sub),
+		Name:      name,
+		Help:      help,
+	}
+	return prometheus.NewGaugeVec(
+		prometheus.GaugeOpts(applyCollectorOptions(prototype, opts...)),
+		labels,
+	)
+}
+
+func newV3HistogramVec(sub, name, help string, labels []string, opts ...CollectorOption) *prometheus.HistogramVec {
+	prototype := prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: strings.ToLower(sub),
+		Name:      name,
+		Help:      help,
+		Buckets:   prometheus.DefBuckets,
+	}
+	return prometheus.NewHistogramVec(
+		applyHistogramOptions(prototype, opts...),
+		labels,
+	)
+}
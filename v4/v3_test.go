@@ -0,0 +1,153 @@
+package promgrpc_test
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piotrkowalczuk/promgrpc/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+)
+
+func TestV3ServerStatsHandler(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.V3ServerStatsHandler()
+	ctx = metadata.NewIncomingContext(ctx, metadata.MD{"user-agent": []string{"fake-user-agent"}})
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+		FullMethodName: "/service/Method",
+		FailFast:       true,
+	})
+	h.HandleRPC(ctx, &stats.Begin{
+		IsClientStream: true,
+		IsServerStream: true,
+	})
+	h.HandleRPC(ctx, &stats.InPayload{})
+	h.HandleRPC(ctx, &stats.InPayload{})
+	h.HandleRPC(ctx, &stats.OutPayload{})
+	h.HandleRPC(ctx, &stats.End{
+		Error: status.Error(codes.Unavailable, "unavailable"),
+	})
+
+	const metadata = `
+		# HELP grpc_server_errors_total Total number of errors that happen during RPC calles on server side.
+		# TYPE grpc_server_errors_total counter
+		# HELP grpc_server_received_messages_total Total number of RPC messages received by server.
+		# TYPE grpc_server_received_messages_total counter
+		# HELP grpc_server_requests Number of currently processed server side rpc requests.
+		# TYPE grpc_server_requests gauge
+		# HELP grpc_server_requests_total Total number of RPC requests received by server.
+		# TYPE grpc_server_requests_total counter
+	`
+	expected := `
+		grpc_server_errors_total{code="Unavailable",handler="Method",service="service",type="bidirectional_stream",user_agent="fake-user-agent"} 1
+		grpc_server_received_messages_total{handler="Method",service="service",user_agent="fake-user-agent"} 2
+		grpc_server_requests{fail_fast="true",handler="Method",service="service",user_agent="fake-user-agent"} 0
+		grpc_server_requests_total{code="Unavailable",handler="Method",service="service",type="bidirectional_stream",user_agent="fake-user-agent"} 1
+	`
+
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected),
+		"grpc_server_errors_total",
+		"grpc_server_received_messages_total",
+		"grpc_server_requests",
+		"grpc_server_requests_total",
+	); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestV3ServerStatsHandler_connections(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.V3ServerStatsHandler()
+	// Connections are tagged before any user agent is known.
+	ctx = h.TagConn(ctx, &stats.ConnTagInfo{
+		LocalAddr:  &net.TCPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 80},
+		RemoteAddr: &net.TCPAddr{IP: net.IPv4(4, 3, 2, 1), Port: 111},
+	})
+	h.HandleConn(ctx, &stats.ConnBegin{})
+
+	const metadata = `
+		# HELP grpc_server_connections Number of currently opened server side connections.
+		# TYPE grpc_server_connections gauge
+	`
+	expected := `
+		grpc_server_connections{local_addr="1.2.3.4:80",remote_addr="4.3.2.1",user_agent="not-set"} 1
+	`
+
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected), "grpc_server_connections"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestV3ClientStatsHandler(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.V3ClientStatsHandler()
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+		FullMethodName: "/service/Method",
+		FailFast:       true,
+	})
+	h.HandleRPC(ctx, &stats.Begin{
+		Client: true,
+	})
+	h.HandleRPC(ctx, &stats.OutPayload{
+		Client: true,
+	})
+	h.HandleRPC(ctx, &stats.InPayload{
+		Client: true,
+	})
+	h.HandleRPC(ctx, &stats.End{
+		Client: true,
+	})
+
+	const metadata = `
+		# HELP grpc_client_requests_total Total number of RPC requests made by client.
+		# TYPE grpc_client_requests_total counter
+		# HELP grpc_client_send_messages_total Total number of RPC messages send.
+		# TYPE grpc_client_send_messages_total counter
+	`
+	expected := `
+		grpc_client_requests_total{code="OK",handler="Method",service="service",type="unary"} 1
+		grpc_client_send_messages_total{handler="Method",service="service"} 1
+	`
+
+	if err := testutil.CollectAndCompare(h, strings.NewReader(metadata+expected),
+		"grpc_client_requests_total",
+		"grpc_client_send_messages_total",
+	); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMigrationStatsHandler(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	registerCollector(t, reg, promgrpc.MigrationServerStatsHandler())
+	registerCollector(t, reg, promgrpc.MigrationClientStatsHandler())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h := promgrpc.MigrationServerStatsHandler(
+		promgrpc.WithTypeLabel(),
+	)
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{
+		FullMethodName: "/service/Method",
+	})
+	h.HandleRPC(ctx, &stats.Begin{})
+	h.HandleRPC(ctx, &stats.End{})
+
+	if n := testutil.CollectAndCount(h, "grpc_server_responses_sent_total", "grpc_server_requests_total"); n != 2 {
+		t.Fatalf("unexpected number of series, expected 2 but got %d", n)
+	}
+}